@@ -0,0 +1,308 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultSaturationDuration = time.Minute
+	defaultWaitRateThreshold  = 1
+	defaultChurnRateThreshold = 1
+
+	// minimumConnMaxLifetime is the minimum suggested value for sql.DB.SetConnMaxLifetime.
+	minimumConnMaxLifetime = time.Minute
+)
+
+// PoolAlertKind is the kind of problem detected by a PoolAnalyzer.
+type PoolAlertKind string
+
+const (
+	// PoolAlertSaturated is raised when all the connections of the pool stay in use.
+	PoolAlertSaturated PoolAlertKind = "saturated"
+	// PoolAlertWaiting is raised when the callers wait for connections too often.
+	PoolAlertWaiting PoolAlertKind = "waiting"
+	// PoolAlertIdleChurn is raised when too many connections are closed because of SetMaxIdleConns.
+	PoolAlertIdleChurn PoolAlertKind = "idle_churn"
+	// PoolAlertLifetimeChurn is raised when too many connections are closed because of SetConnMaxLifetime.
+	PoolAlertLifetimeChurn PoolAlertKind = "lifetime_churn"
+)
+
+// PoolAlert is a problem detected by a PoolAnalyzer. An alert is raised once when the problem starts, and again only
+// after it has stopped.
+type PoolAlert struct {
+	Kind PoolAlertKind
+	// Attributes identifies the pool.
+	Attributes attribute.Set
+	Stats      sql.DBStats
+	Message    string
+}
+
+// PoolRecommendation suggests the settings of a pool from the observed workload. The zero values mean that there is no
+// suggestion for the setting.
+type PoolRecommendation struct {
+	// Attributes identifies the pool.
+	Attributes attribute.Set
+
+	// MaxOpenConns is the suggested value for sql.DB.SetMaxOpenConns. It is only suggested for the pools that have a
+	// limit: database/sql makes the callers wait only when the limit is reached, so the waits of a pool without a limit
+	// were caused by a limit that has been lifted since.
+	MaxOpenConns int
+	// MaxIdleConns is the suggested value for sql.DB.SetMaxIdleConns.
+	MaxIdleConns int
+	// ConnMaxLifetime is the suggested value for sql.DB.SetConnMaxLifetime.
+	ConnMaxLifetime time.Duration
+
+	Reasons []string
+}
+
+// PoolAnalyzerOption allows for managing the PoolAnalyzer configuration using functional options.
+type PoolAnalyzerOption interface {
+	applyPoolAnalyzerOptions(a *PoolAnalyzer)
+}
+
+type poolAnalyzerOptionFunc func(a *PoolAnalyzer)
+
+func (f poolAnalyzerOptionFunc) applyPoolAnalyzerOptions(a *PoolAnalyzer) {
+	f(a)
+}
+
+// WithSaturationDuration sets how long all the connections must stay in use before the pool is considered saturated.
+// Default is 1 minute.
+func WithSaturationDuration(d time.Duration) PoolAnalyzerOption {
+	return poolAnalyzerOptionFunc(func(a *PoolAnalyzer) {
+		a.saturationDuration = d
+	})
+}
+
+// WithWaitRateThreshold sets the number of connections waited for per second above which an alert is raised. Default
+// is 1.
+func WithWaitRateThreshold(perSecond float64) PoolAnalyzerOption {
+	return poolAnalyzerOptionFunc(func(a *PoolAnalyzer) {
+		a.waitRateThreshold = perSecond
+	})
+}
+
+// WithChurnRateThreshold sets the number of connections closed per second by SetMaxIdleConns or SetConnMaxLifetime
+// above which an alert is raised. Default is 1.
+func WithChurnRateThreshold(perSecond float64) PoolAnalyzerOption {
+	return poolAnalyzerOptionFunc(func(a *PoolAnalyzer) {
+		a.churnRateThreshold = perSecond
+	})
+}
+
+// WithAlertHandler sets the function that handles the alerts. By default, the alerts are logged as warnings with
+// slog.Default().
+func WithAlertHandler(f func(ctx context.Context, alert PoolAlert)) PoolAnalyzerOption {
+	return poolAnalyzerOptionFunc(func(a *PoolAnalyzer) {
+		a.handleAlert = f
+	})
+}
+
+// PoolAnalyzer detects the saturation and the churn of the pools observed by a StatsRecorder, and suggests their
+// settings. It only analyzes the statistics that are read by the StatsRecorder, so the detection is as frequent as the
+// collection of the metrics.
+//
+// Use WithPoolAnalyzer to analyze the pools of a StatsRecorder or of RecordStats.
+type PoolAnalyzer struct {
+	saturationDuration time.Duration
+	waitRateThreshold  float64
+	churnRateThreshold float64
+	handleAlert        func(ctx context.Context, alert PoolAlert)
+
+	mu    sync.Mutex
+	pools map[*sql.DB]*poolAnalysis
+}
+
+type poolAnalysis struct {
+	attributes attribute.Set
+
+	stats      sql.DBStats
+	observedAt time.Time
+
+	saturatedSince time.Time
+	peakInUse      int
+
+	// The rates of the latest observation interval, per second.
+	waitRate          float64
+	idleChurnRate     float64
+	lifetimeChurnRate float64
+
+	alerts map[PoolAlertKind]bool
+}
+
+// NewPoolAnalyzer creates a new PoolAnalyzer.
+func NewPoolAnalyzer(opts ...PoolAnalyzerOption) *PoolAnalyzer {
+	a := &PoolAnalyzer{
+		saturationDuration: defaultSaturationDuration,
+		waitRateThreshold:  defaultWaitRateThreshold,
+		churnRateThreshold: defaultChurnRateThreshold,
+		handleAlert:        logPoolAlert,
+		pools:              make(map[*sql.DB]*poolAnalysis),
+	}
+
+	for _, opt := range opts {
+		opt.applyPoolAnalyzerOptions(a)
+	}
+
+	return a
+}
+
+// Recommendations suggests the settings of the pools that have a problem.
+func (a *PoolAnalyzer) Recommendations() []PoolRecommendation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	recommendations := make([]PoolRecommendation, 0, len(a.pools))
+
+	for _, p := range a.pools {
+		if r, ok := p.recommend(); ok {
+			recommendations = append(recommendations, r)
+		}
+	}
+
+	return recommendations
+}
+
+// analyze analyzes the latest statistics of a pool and returns the alerts that are raised.
+func (a *PoolAnalyzer) analyze(db *sql.DB, attrs attribute.Set, stats sql.DBStats, now time.Time) []PoolAlert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p, ok := a.pools[db]
+	if !ok {
+		p = &poolAnalysis{alerts: make(map[PoolAlertKind]bool)}
+		a.pools[db] = p
+	}
+
+	p.attributes = attrs
+
+	return p.observe(a, stats, now)
+}
+
+func (a *PoolAnalyzer) forget(db *sql.DB) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.pools, db)
+}
+
+func (p *poolAnalysis) observe(a *PoolAnalyzer, stats sql.DBStats, now time.Time) []PoolAlert {
+	saturated := stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections
+
+	switch {
+	case !saturated:
+		p.saturatedSince = time.Time{}
+
+	case p.saturatedSince.IsZero():
+		p.saturatedSince = now
+	}
+
+	p.peakInUse = max(p.peakInUse, stats.InUse)
+
+	if !p.observedAt.IsZero() {
+		if elapsed := now.Sub(p.observedAt).Seconds(); elapsed > 0 {
+			p.waitRate = float64(stats.WaitCount-p.stats.WaitCount) / elapsed
+			p.idleChurnRate = float64(stats.MaxIdleClosed-p.stats.MaxIdleClosed) / elapsed
+			p.lifetimeChurnRate = float64(stats.MaxLifetimeClosed-p.stats.MaxLifetimeClosed) / elapsed
+		}
+	}
+
+	p.stats = stats
+	p.observedAt = now
+
+	var alerts []PoolAlert
+
+	raise := func(kind PoolAlertKind, active bool, format string, args ...any) {
+		if active && !p.alerts[kind] {
+			alerts = append(alerts, PoolAlert{
+				Kind:       kind,
+				Attributes: p.attributes,
+				Stats:      stats,
+				Message:    fmt.Sprintf(format, args...),
+			})
+		}
+
+		p.alerts[kind] = active
+	}
+
+	raise(PoolAlertSaturated, saturated && now.Sub(p.saturatedSince) >= a.saturationDuration,
+		"all the %d connections have been in use for %s", stats.MaxOpenConnections, now.Sub(p.saturatedSince))
+	raise(PoolAlertWaiting, p.waitRate > a.waitRateThreshold,
+		"%.2f connections per second were waited for", p.waitRate)
+	raise(PoolAlertIdleChurn, p.idleChurnRate > a.churnRateThreshold,
+		"%.2f connections per second were closed by SetMaxIdleConns", p.idleChurnRate)
+	raise(PoolAlertLifetimeChurn, p.lifetimeChurnRate > a.churnRateThreshold,
+		"%.2f connections per second were closed by SetConnMaxLifetime", p.lifetimeChurnRate)
+
+	return alerts
+}
+
+func (p *poolAnalysis) recommend() (PoolRecommendation, bool) {
+	r := PoolRecommendation{Attributes: p.attributes}
+
+	saturated, waiting := p.alerts[PoolAlertSaturated], p.alerts[PoolAlertWaiting]
+
+	// There is no limit to raise without a maximum number of open connections, see PoolRecommendation.MaxOpenConns.
+	if (saturated || waiting) && p.stats.MaxOpenConnections > 0 {
+		// Give the pool half more room than what was exhausted.
+		r.MaxOpenConns = int(math.Ceil(float64(p.stats.MaxOpenConnections) * 1.5))
+
+		var reason string
+
+		switch {
+		case saturated && waiting:
+			reason = fmt.Sprintf("all the %d connections were in use and %.2f connections per second were waited for",
+				p.stats.MaxOpenConnections, p.waitRate)
+
+		case saturated:
+			reason = fmt.Sprintf("all the %d connections were in use", p.stats.MaxOpenConnections)
+
+		default:
+			reason = fmt.Sprintf("%.2f connections per second were waited for with at most %d connections",
+				p.waitRate, p.stats.MaxOpenConnections)
+		}
+
+		r.Reasons = append(r.Reasons, reason)
+	}
+
+	if p.alerts[PoolAlertIdleChurn] {
+		// Keep enough idle connections for the peak of concurrent use, instead of closing and opening them again.
+		r.MaxIdleConns = p.peakInUse
+		r.Reasons = append(r.Reasons, fmt.Sprintf(
+			"%.2f connections per second were closed by SetMaxIdleConns while up to %d were in use",
+			p.idleChurnRate, p.peakInUse,
+		))
+	}
+
+	if p.alerts[PoolAlertLifetimeChurn] && p.stats.OpenConnections > 0 {
+		// The connections live about open/rate, doubling that halves the churn.
+		lifetime := time.Duration(float64(p.stats.OpenConnections) / p.lifetimeChurnRate * float64(time.Second))
+
+		r.ConnMaxLifetime = max(2*lifetime, minimumConnMaxLifetime).Round(time.Second)
+		r.Reasons = append(r.Reasons, fmt.Sprintf(
+			"%.2f connections per second were closed by SetConnMaxLifetime, they lived about %s",
+			p.lifetimeChurnRate, lifetime.Round(time.Second),
+		))
+	}
+
+	return r, len(r.Reasons) > 0
+}
+
+func logPoolAlert(ctx context.Context, alert PoolAlert) {
+	attrs := make([]slog.Attr, 0, alert.Attributes.Len()+1)
+	attrs = append(attrs, slog.String("alert", string(alert.Kind)))
+
+	for _, kv := range alert.Attributes.ToSlice() {
+		attrs = append(attrs, slog.String(string(kv.Key), kv.Value.Emit()))
+	}
+
+	slog.Default().LogAttrs(ctx, slog.LevelWarn, "otelsql: "+alert.Message, attrs...)
+}
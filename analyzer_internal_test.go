@@ -0,0 +1,233 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestPoolAnalyzer_Saturated(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer()
+	db := &sql.DB{}
+	attrs := attribute.NewSet(dbInstance.String("test"))
+	start := time.Now()
+
+	saturated := sql.DBStats{MaxOpenConnections: 10, OpenConnections: 10, InUse: 10}
+
+	assert.Empty(t, a.analyze(db, attrs, saturated, start))
+	assert.Empty(t, a.analyze(db, attrs, saturated, start.Add(30*time.Second)))
+
+	alerts := a.analyze(db, attrs, saturated, start.Add(time.Minute))
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, PoolAlertSaturated, alerts[0].Kind)
+	assert.Equal(t, attrs, alerts[0].Attributes)
+	assert.Equal(t, "all the 10 connections have been in use for 1m0s", alerts[0].Message)
+
+	// The alert is raised only once.
+	assert.Empty(t, a.analyze(db, attrs, saturated, start.Add(2*time.Minute)))
+
+	expected := []PoolRecommendation{{
+		Attributes:   attrs,
+		MaxOpenConns: 15,
+		Reasons:      []string{"all the 10 connections were in use"},
+	}}
+
+	assert.Equal(t, expected, a.Recommendations())
+
+	// The pool recovers.
+	assert.Empty(t, a.analyze(db, attrs, sql.DBStats{MaxOpenConnections: 10, OpenConnections: 10, InUse: 2}, start.Add(3*time.Minute)))
+	assert.Empty(t, a.Recommendations())
+}
+
+func TestPoolAnalyzer_Waiting(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer(WithWaitRateThreshold(5))
+	db := &sql.DB{}
+	start := time.Now()
+
+	assert.Empty(t, a.analyze(db, attribute.NewSet(), sql.DBStats{WaitCount: 10}, start))
+	assert.Empty(t, a.analyze(db, attribute.NewSet(), sql.DBStats{WaitCount: 50}, start.Add(10*time.Second)))
+
+	alerts := a.analyze(db, attribute.NewSet(), sql.DBStats{WaitCount: 150}, start.Add(20*time.Second))
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, PoolAlertWaiting, alerts[0].Kind)
+	assert.Equal(t, "10.00 connections per second were waited for", alerts[0].Message)
+
+	// The pool is unlimited, the waits were caused by a limit that has been lifted since, there is nothing to suggest.
+	assert.Empty(t, a.Recommendations())
+}
+
+func TestPoolAnalyzer_Waiting_Limited(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer(WithWaitRateThreshold(5))
+	db := &sql.DB{}
+	start := time.Now()
+
+	// The connections are waited for, but they are not all in use long enough for the pool to be saturated.
+	assert.Empty(t, a.analyze(db, attribute.NewSet(), sql.DBStats{MaxOpenConnections: 10, InUse: 10, WaitCount: 10}, start))
+
+	alerts := a.analyze(db, attribute.NewSet(), sql.DBStats{MaxOpenConnections: 10, InUse: 4, WaitCount: 110}, start.Add(10*time.Second))
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, PoolAlertWaiting, alerts[0].Kind)
+
+	expected := []PoolRecommendation{{
+		Attributes:   attribute.NewSet(),
+		MaxOpenConns: 15,
+		Reasons:      []string{"10.00 connections per second were waited for with at most 10 connections"},
+	}}
+
+	assert.Equal(t, expected, a.Recommendations())
+
+	// The pool is also saturated.
+	saturated := sql.DBStats{MaxOpenConnections: 10, InUse: 10, WaitCount: 1110}
+
+	assert.Empty(t, a.analyze(db, attribute.NewSet(), saturated, start.Add(20*time.Second)))
+
+	alerts = a.analyze(db, attribute.NewSet(), sql.DBStats{MaxOpenConnections: 10, InUse: 10, WaitCount: 1710}, start.Add(80*time.Second))
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, PoolAlertSaturated, alerts[0].Kind)
+
+	expected[0].Reasons = []string{"all the 10 connections were in use and 10.00 connections per second were waited for"}
+
+	assert.Equal(t, expected, a.Recommendations())
+}
+
+func TestPoolAnalyzer_Churn(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer()
+	db := &sql.DB{}
+	start := time.Now()
+
+	assert.Empty(t, a.analyze(db, attribute.NewSet(), sql.DBStats{OpenConnections: 100, InUse: 40}, start))
+
+	alerts := a.analyze(db, attribute.NewSet(), sql.DBStats{
+		OpenConnections:   100,
+		InUse:             20,
+		MaxIdleClosed:     50,
+		MaxLifetimeClosed: 15,
+	}, start.Add(10*time.Second))
+
+	require.Len(t, alerts, 2)
+	assert.Equal(t, PoolAlertIdleChurn, alerts[0].Kind)
+	assert.Equal(t, "5.00 connections per second were closed by SetMaxIdleConns", alerts[0].Message)
+	assert.Equal(t, PoolAlertLifetimeChurn, alerts[1].Kind)
+	assert.Equal(t, "1.50 connections per second were closed by SetConnMaxLifetime", alerts[1].Message)
+
+	expected := []PoolRecommendation{{
+		Attributes:      attribute.NewSet(),
+		MaxIdleConns:    40,
+		ConnMaxLifetime: 2*time.Minute + 13*time.Second,
+		Reasons: []string{
+			"5.00 connections per second were closed by SetMaxIdleConns while up to 40 were in use",
+			"1.50 connections per second were closed by SetConnMaxLifetime, they lived about 1m7s",
+		},
+	}}
+
+	assert.Equal(t, expected, a.Recommendations())
+}
+
+func TestPoolAnalyzer_Forget(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer(WithSaturationDuration(0))
+	db := &sql.DB{}
+
+	alerts := a.analyze(db, attribute.NewSet(), sql.DBStats{MaxOpenConnections: 1, InUse: 1}, time.Now())
+
+	require.Len(t, alerts, 1)
+	assert.Len(t, a.Recommendations(), 1)
+
+	a.forget(db)
+
+	assert.Empty(t, a.Recommendations())
+}
+
+func TestStatsRecorder_PoolAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	var alerts []PoolAlert
+
+	a := NewPoolAnalyzer(
+		WithSaturationDuration(0),
+		WithAlertHandler(func(_ context.Context, alert PoolAlert) {
+			alerts = append(alerts, alert)
+		}),
+	)
+
+	r := &StatsRecorder{analyzer: a}
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close() // nolint: errcheck
+	})
+
+	db.SetMaxOpenConns(1)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close() // nolint: errcheck
+	})
+
+	r.Observe(db)
+	r.observe(context.Background(), func(dbStats, metric.MeasurementOption) {})
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, PoolAlertSaturated, alerts[0].Kind)
+
+	r.Forget(db)
+
+	assert.Empty(t, a.Recommendations())
+}
+
+func TestStatsRecorder_Unregister(t *testing.T) {
+	t.Parallel()
+
+	a := NewPoolAnalyzer(WithSaturationDuration(0))
+
+	r, err := NewStatsRecorder(WithPoolAnalyzer(a))
+	require.NoError(t, err)
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close() // nolint: errcheck
+	})
+
+	db.SetMaxOpenConns(1)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close() // nolint: errcheck
+	})
+
+	r.Observe(db)
+	r.observe(context.Background(), func(dbStats, metric.MeasurementOption) {})
+
+	require.Len(t, a.Recommendations(), 1)
+	require.NoError(t, r.Unregister())
+
+	assert.Empty(t, a.Recommendations())
+}
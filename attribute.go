@@ -21,9 +21,98 @@ const (
 	// Type: string.
 	// Required: No.
 	dbSQLRowsNextLatencyAvg = attribute.Key("db.sql.rows_next.latency_avg")
+	// Type: string.
+	// Required: No.
+	dbSQLRowsNextFirstRowLatency = attribute.Key("db.sql.rows_next.first_row_latency")
+	// Type: string.
+	// Required: No.
+	dbSQLRowsNextIterationTime = attribute.Key("db.sql.rows_next.iteration_time")
+	// Type: string.
+	// Required: No.
+	dbSQLRowsNextMaxGap = attribute.Key("db.sql.rows_next.max_gap")
+	// Type: string.
+	// Required: No.
+	dbSQLRowsDuration = attribute.Key("db.sql.rows.duration")
+	// Type: int64.
+	// Required: No.
+	dbSQLResultSetIndex = attribute.Key("db.sql.result_set.index")
+	// Type: int64.
+	// Required: No.
+	dbSQLResultSetRowsCount = attribute.Key("db.sql.result_set.rows_count")
+	// Type: string.
+	// Required: No.
+	dbSQLStmtAge = attribute.Key("db.sql.stmt.age")
+	// Type: int64.
+	// Required: No.
+	dbSQLStmtUseCount = attribute.Key("db.sql.stmt.use_count")
+	// Type: string.
+	// Required: No.
+	dbSQLQueryFingerprint = attribute.Key("db.sql.query.fingerprint")
+	// Type: int64.
+	// Required: No.
+	dbSQLNPlusOneCount = attribute.Key("db.sql.n_plus_one.count")
+	// Type: int64.
+	// Required: No.
+	dbSQLNPlusOneThreshold = attribute.Key("db.sql.n_plus_one.threshold")
+	// Type: string.
+	// Required: No.
+	dbSQLLatency = attribute.Key("db.sql.latency")
+	// Type: int64.
+	// Required: No.
+	dbSQLLastInsertID = attribute.Key("db.sql.last_insert_id")
+	// Type: bool.
+	// Required: No.
+	dbSQLSkipped = attribute.Key("db.sql.skipped")
+	// Type: int64.
+	// Required: No.
+	dbClientConnectionID = attribute.Key("db.client.connection.id")
+	// Type: string.
+	// Required: No.
+	dbClientConnectionAge = attribute.Key("db.client.connection.age")
+	// Type: int64.
+	// Required: No.
+	dbClientConnectionUseCount = attribute.Key("db.client.connection.use_count")
+	// Type: string.
+	// Required: No.
+	dbClientConnectionDiscardReason = attribute.Key("db.client.connection.discard_reason")
+	// Type: string.
+	// Required: No.
+	dbClientConnectionState = attribute.Key("db.client.connection.state")
+	// Type: string.
+	// Required: No.
+	dbClientConnectionPoolName = attribute.Key("db.client.connection.pool.name")
+	// Type: string.
+	// Required: No.
+	dbClientConnectionWaitTimeKey = attribute.Key("db.client.connection.wait_time")
+	// Type: string.
+	// Required: No.
+	dbResponseStatusCode = attribute.Key("db.response.status_code")
+	// Type: string.
+	// Required: No.
+	errorType = attribute.Key("error.type")
+	// Type: int64.
+	// Required: No.
+	dbResponseReturnedRows = attribute.Key("db.response.returned_rows")
+	// Type: int64.
+	// Required: No.
+	dbResponseReturnedBytes = attribute.Key("db.response.returned_bytes")
+	// Type: int64.
+	// Required: No.
+	dbSQLRowsAffected = attribute.Key("db.sql.rows_affected")
 )
 
 var (
-	dbSQLStatusOK    = dbSQLStatus.String("OK")
-	dbSQLStatusERROR = dbSQLStatus.String("ERROR")
+	dbSQLStatusOK       = dbSQLStatus.String("OK")
+	dbSQLStatusERROR    = dbSQLStatus.String("ERROR")
+	dbSQLStatusCANCELED = dbSQLStatus.String("CANCELED")
+
+	dbClientConnectionStateIdle = dbClientConnectionState.String("idle")
+	dbClientConnectionStateUsed = dbClientConnectionState.String("used")
+)
+
+const (
+	// connDiscardReasonResetSession is used when ResetSession returns driver.ErrBadConn.
+	connDiscardReasonResetSession = "reset_session"
+	// connDiscardReasonInvalid is used when IsValid reports that the connection is no longer valid.
+	connDiscardReasonInvalid = "invalid"
 )
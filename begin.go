@@ -69,6 +69,10 @@ func beginWrapTx(r methodRecorder, t methodTracer) beginFuncMiddleware {
 				return nil, err
 			}
 
+			if s := connStateFromContext(ctx); s != nil {
+				s.inTx.Store(true)
+			}
+
 			shouldTrace, _ := t.ShouldTrace(ctx)
 
 			return wrapTx(ctx, tx, r, tracerOrNil(t, shouldTrace)), nil
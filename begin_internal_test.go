@@ -23,7 +23,7 @@ func BenchmarkBeginStats(b *testing.B) {
 	count, err := meter.Int64Counter("calls")
 	require.NoError(b, err)
 
-	r := newMethodRecorder(histogram.Record, count.Add,
+	r := newMethodRecorder(histogram.Record, count.Add, nil,
 		semconv.DBSystemOtherSQL,
 		dbInstance.String("test"),
 	)
@@ -192,7 +192,7 @@ func TestBeginStats(t *testing.T) {
 					count, err := meter.Int64Counter(dbSQLClientCalls)
 					require.NoError(t, err)
 
-					r := newMethodRecorder(histogram.Record, count.Add,
+					r := newMethodRecorder(histogram.Record, count.Add, nil,
 						semconv.DBSystemOtherSQL,
 						dbInstance.String("test"),
 					)
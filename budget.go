@@ -0,0 +1,329 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQueryBudgetExceeded is returned by the execs and the queries beyond the budget, with FailOnQueryBudgetExceeded().
+var ErrQueryBudgetExceeded = errors.New("otelsql: query budget exceeded")
+
+type queryBudgetCtxKey struct{}
+
+// QueryStats summarizes the calls made with a context, see WithQueryBudget.
+type QueryStats struct {
+	// Queries is the number of execs and queries.
+	Queries int
+	// Duration is the total time of the execs, the queries, the prepares, the commits and the rollbacks.
+	Duration time.Duration
+	// Rows is the number of rows returned by the queries, counted when the rows are closed.
+	Rows int64
+	// Errors is the number of failed calls.
+	Errors int
+	// SlowestQuery is the slowest exec or query, and SlowestDuration is its duration.
+	SlowestQuery    string
+	SlowestDuration time.Duration
+	// Exceeded is true if the budget has been exceeded.
+	Exceeded bool
+}
+
+// QueryBudgetOption allows for managing the budget of the queries using functional options.
+type QueryBudgetOption interface {
+	applyQueryBudgetOptions(o *queryBudgetOptions)
+}
+
+// queryBudgetOptions holds the budget of the queries.
+type queryBudgetOptions struct {
+	// maxQueries is the maximum number of execs and queries, no limit if zero.
+	maxQueries int
+
+	// maxDuration is the maximum total time of the calls, no limit if zero.
+	maxDuration time.Duration
+
+	// fail rejects the execs and the queries beyond the budget.
+	fail bool
+
+	// onExceeded is called when the budget is exceeded.
+	onExceeded func(ctx context.Context, stats QueryStats)
+}
+
+// queryBudget collects the stats of the calls made with a context and checks them against the budget.
+type queryBudget struct {
+	opts queryBudgetOptions
+
+	mu    sync.Mutex
+	stats QueryStats
+}
+
+// reserve checks whether an exec or a query can be executed within the budget.
+func (b *queryBudget) reserve(ctx context.Context) error {
+	if !b.opts.fail {
+		return nil
+	}
+
+	b.mu.Lock()
+
+	full := b.opts.maxQueries > 0 && b.stats.Queries >= b.opts.maxQueries ||
+		b.opts.maxDuration > 0 && b.stats.Duration >= b.opts.maxDuration
+
+	if !full {
+		b.mu.Unlock()
+
+		return nil
+	}
+
+	b.stats.Errors++
+	b.exceed(ctx)
+
+	return ErrQueryBudgetExceeded
+}
+
+// record adds a call to the stats. The query is empty for the calls that are not execs or queries.
+func (b *queryBudget) record(ctx context.Context, query string, d time.Duration, err error) {
+	b.mu.Lock()
+
+	b.stats.Duration += d
+
+	if err != nil {
+		b.stats.Errors++
+	}
+
+	if query != "" {
+		b.stats.Queries++
+
+		if d > b.stats.SlowestDuration {
+			b.stats.SlowestQuery = query
+			b.stats.SlowestDuration = d
+		}
+	}
+
+	if b.opts.maxQueries > 0 && b.stats.Queries > b.opts.maxQueries ||
+		b.opts.maxDuration > 0 && b.stats.Duration > b.opts.maxDuration {
+		b.exceed(ctx)
+
+		return
+	}
+
+	b.mu.Unlock()
+}
+
+// exceed marks the budget as exceeded and unlocks the stats. The callback is only called the first time, without the
+// lock.
+func (b *queryBudget) exceed(ctx context.Context) {
+	if b.stats.Exceeded {
+		b.mu.Unlock()
+
+		return
+	}
+
+	b.stats.Exceeded = true
+	stats := b.stats
+
+	b.mu.Unlock()
+
+	if b.opts.onExceeded != nil {
+		b.opts.onExceeded(ctx, stats)
+	}
+}
+
+func (b *queryBudget) addRows(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stats.Rows += n
+}
+
+func (b *queryBudget) snapshot() QueryStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stats
+}
+
+// WithQueryBudget attaches a collector to the context. The execs, the queries, the prepares, the commits and the
+// rollbacks made with the context, including inside the transactions begun with it, add their stats to the collector,
+// see QueryStatsFromContext. For example:
+//
+//	ctx = otelsql.WithQueryBudget(ctx, otelsql.MaxQueries(100))
+//
+//	handle(ctx)
+//
+//	stats, _ := otelsql.QueryStatsFromContext(ctx)
+//	slog.InfoContext(ctx, "queries", "count", stats.Queries, "duration", stats.Duration)
+func WithQueryBudget(ctx context.Context, opts ...QueryBudgetOption) context.Context {
+	b := &queryBudget{}
+
+	for _, o := range opts {
+		o.applyQueryBudgetOptions(&b.opts)
+	}
+
+	return context.WithValue(ctx, queryBudgetCtxKey{}, b)
+}
+
+// QueryStatsFromContext returns the stats collected since WithQueryBudget attached a collector to the context.
+func QueryStatsFromContext(ctx context.Context) (QueryStats, bool) {
+	b := queryBudgetFromContext(ctx)
+	if b == nil {
+		return QueryStats{}, false
+	}
+
+	return b.snapshot(), true
+}
+
+func queryBudgetFromContext(ctx context.Context) *queryBudget {
+	b, ok := ctx.Value(queryBudgetCtxKey{}).(*queryBudget)
+	if !ok {
+		return nil
+	}
+
+	return b
+}
+
+type queryBudgetOptionFunc func(o *queryBudgetOptions)
+
+func (f queryBudgetOptionFunc) applyQueryBudgetOptions(o *queryBudgetOptions) {
+	f(o)
+}
+
+// MaxQueries sets the maximum number of execs and queries of the budget.
+func MaxQueries(n int) QueryBudgetOption {
+	return queryBudgetOptionFunc(func(o *queryBudgetOptions) {
+		o.maxQueries = n
+	})
+}
+
+// MaxQueryTime sets the maximum total time of the calls of the budget.
+func MaxQueryTime(d time.Duration) QueryBudgetOption {
+	return queryBudgetOptionFunc(func(o *queryBudgetOptions) {
+		o.maxDuration = d
+	})
+}
+
+// OnQueryBudgetExceeded sets a callback that is called the first time the budget is exceeded, for example to log a
+// warning or to fail a test.
+func OnQueryBudgetExceeded(f func(ctx context.Context, stats QueryStats)) QueryBudgetOption {
+	return queryBudgetOptionFunc(func(o *queryBudgetOptions) {
+		o.onExceeded = f
+	})
+}
+
+// FailOnQueryBudgetExceeded rejects the execs and the queries beyond the budget with ErrQueryBudgetExceeded, without
+// executing them. The prepares, the commits and the rollbacks are never rejected.
+func FailOnQueryBudgetExceeded() QueryBudgetOption {
+	return queryBudgetOptionFunc(func(o *queryBudgetOptions) {
+		o.fail = true
+	})
+}
+
+// execQueryBudget adds the exec to the stats of the collector of the context, if any.
+func execQueryBudget() execContextFuncMiddleware {
+	return func(next execContextFunc) execContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			b := queryBudgetFromContext(ctx)
+			if b == nil {
+				return next(ctx, query, args)
+			}
+
+			if err := b.reserve(ctx); err != nil {
+				return nil, err
+			}
+
+			start := time.Now()
+
+			result, err := next(ctx, query, args)
+
+			observeUnlessSkipped(err, func() {
+				b.record(ctx, query, time.Since(start), err)
+			})
+
+			return result, err
+		}
+	}
+}
+
+// queryQueryBudget adds the query and its rows to the stats of the collector of the context, if any.
+func queryQueryBudget() queryContextFuncMiddleware {
+	return func(next queryContextFunc) queryContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+			b := queryBudgetFromContext(ctx)
+			if b == nil {
+				return next(ctx, query, args)
+			}
+
+			if err := b.reserve(ctx); err != nil {
+				return nil, err
+			}
+
+			start := time.Now()
+
+			hooksCtx, h, attached := contextWithRowsHooks(ctx)
+
+			result, err := next(hooksCtx, query, args)
+
+			observeUnlessSkipped(err, func() {
+				b.record(ctx, query, time.Since(start), err)
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			var count int64
+
+			h.onNext = append(h.onNext, func(_ []driver.Value, err error) {
+				if err == nil {
+					count++
+				}
+			})
+			h.onClose = append(h.onClose, func() {
+				b.addRows(count)
+			})
+
+			return h.wrap(result, attached), nil
+		}
+	}
+}
+
+// prepareQueryBudget adds the prepare to the stats of the collector of the context, if any.
+func prepareQueryBudget() prepareContextFuncMiddleware {
+	return func(next prepareContextFunc) prepareContextFunc {
+		return func(ctx context.Context, query string) (driver.Stmt, error) {
+			b := queryBudgetFromContext(ctx)
+			if b == nil {
+				return next(ctx, query)
+			}
+
+			start := time.Now()
+
+			stmt, err := next(ctx, query)
+
+			b.record(ctx, "", time.Since(start), err)
+
+			return stmt, err
+		}
+	}
+}
+
+// txQueryBudget adds the commit or the rollback to the stats of the collector of the context of the transaction, if
+// any.
+func txQueryBudget(ctx context.Context) txFuncMiddleware {
+	return func(next txFunc) txFunc {
+		b := queryBudgetFromContext(ctx)
+		if b == nil {
+			return next
+		}
+
+		return func() error {
+			start := time.Now()
+
+			err := next()
+
+			b.record(ctx, "", time.Since(start), err)
+
+			return err
+		}
+	}
+}
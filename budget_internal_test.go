@@ -0,0 +1,113 @@
+package otelsql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBudget_Record(t *testing.T) {
+	t.Parallel()
+
+	var exceeded []QueryStats
+
+	ctx := WithQueryBudget(context.Background(),
+		MaxQueries(2),
+		OnQueryBudgetExceeded(func(_ context.Context, stats QueryStats) {
+			exceeded = append(exceeded, stats)
+		}),
+	)
+
+	b := queryBudgetFromContext(ctx)
+	require.NotNil(t, b)
+
+	b.record(ctx, "SELECT 1", time.Millisecond, nil)
+	b.record(ctx, "", 2*time.Millisecond, nil)
+	b.record(ctx, "SELECT 2", 3*time.Millisecond, errors.New("error"))
+	b.addRows(5)
+
+	stats, ok := QueryStatsFromContext(ctx)
+
+	require.True(t, ok)
+	assert.Equal(t, QueryStats{
+		Queries:         2,
+		Duration:        6 * time.Millisecond,
+		Rows:            5,
+		Errors:          1,
+		SlowestQuery:    "SELECT 2",
+		SlowestDuration: 3 * time.Millisecond,
+	}, stats)
+	assert.Empty(t, exceeded)
+
+	// The callback is only called the first time.
+	b.record(ctx, "SELECT 3", time.Millisecond, nil)
+	b.record(ctx, "SELECT 4", time.Millisecond, nil)
+
+	require.Len(t, exceeded, 1)
+	assert.Equal(t, 3, exceeded[0].Queries)
+	assert.True(t, exceeded[0].Exceeded)
+}
+
+func TestQueryBudget_Reserve(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		opts     []QueryBudgetOption
+		expected error
+	}{
+		{
+			scenario: "no limit",
+			opts:     []QueryBudgetOption{FailOnQueryBudgetExceeded()},
+		},
+		{
+			scenario: "warn only",
+			opts:     []QueryBudgetOption{MaxQueries(1)},
+		},
+		{
+			scenario: "within the budget",
+			opts:     []QueryBudgetOption{MaxQueries(2), FailOnQueryBudgetExceeded()},
+		},
+		{
+			scenario: "max queries",
+			opts:     []QueryBudgetOption{MaxQueries(1), FailOnQueryBudgetExceeded()},
+			expected: ErrQueryBudgetExceeded,
+		},
+		{
+			scenario: "max query time",
+			opts:     []QueryBudgetOption{MaxQueryTime(time.Millisecond), FailOnQueryBudgetExceeded()},
+			expected: ErrQueryBudgetExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := WithQueryBudget(context.Background(), tc.opts...)
+			b := queryBudgetFromContext(ctx)
+
+			b.record(ctx, "SELECT 1", time.Millisecond, nil)
+
+			assert.Equal(t, tc.expected, b.reserve(ctx))
+
+			stats, _ := QueryStatsFromContext(ctx)
+
+			assert.Equal(t, tc.expected != nil, stats.Exceeded)
+		})
+	}
+}
+
+func TestQueryStatsFromContext_NoBudget(t *testing.T) {
+	t.Parallel()
+
+	stats, ok := QueryStatsFromContext(context.Background())
+
+	assert.False(t, ok)
+	assert.Empty(t, stats)
+}
@@ -0,0 +1,155 @@
+package otelsql
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// maxCallerDepth is the maximum number of frames inspected to find the caller of a method.
+const maxCallerDepth = 64
+
+// defaultCallerSkipPackages are the packages that never issue the queries themselves.
+var defaultCallerSkipPackages = []string{
+	"database/sql",
+	"go.nhat.io/otelsql",
+	"runtime",
+}
+
+// CallerOption allows for managing the capture of the caller location using functional options.
+type CallerOption interface {
+	applyCallerOptions(o *callerOptions)
+}
+
+// callerOptions holds the configuration of the capture of the caller location.
+type callerOptions struct {
+	// skipPackages are the packages skipped to find the caller, in addition to the default ones.
+	skipPackages []string
+
+	// stackTrace records the exception.stacktrace of the errors, from the caller.
+	stackTrace bool
+
+	// methods are the traced methods that capture their caller, all of them if empty.
+	methods []string
+}
+
+// captures checks whether the method captures its caller.
+func (o *callerOptions) captures(method string) bool {
+	return o != nil && (len(o.methods) == 0 || slices.Contains(o.methods, method))
+}
+
+// skips checks whether the function belongs to a skipped package.
+func (o *callerOptions) skips(function string) bool {
+	for _, pkg := range defaultCallerSkipPackages {
+		if inPackage(function, pkg) {
+			return true
+		}
+	}
+
+	for _, pkg := range o.skipPackages {
+		if inPackage(function, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callers returns the program counters of the frames of the caller of the traced method.
+func (o *callerOptions) callers() []uintptr {
+	pcs := make([]uintptr, maxCallerDepth)
+
+	// Skip runtime.Callers and this method.
+	return pcs[:runtime.Callers(2, pcs)]
+}
+
+// frames returns the frames of the program counters, from the first one that does not belong to a skipped package.
+func (o *callerOptions) frames(pcs []uintptr) *runtime.Frames {
+	for i, pc := range pcs {
+		// The program counters are the return addresses, the call is the instruction before.
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && !o.skips(fn.Name()) {
+			return runtime.CallersFrames(pcs[i:])
+		}
+	}
+
+	return runtime.CallersFrames(nil)
+}
+
+// attributes returns the location of the first frame that does not belong to a skipped package.
+func (o *callerOptions) attributes(pcs []uintptr) []attribute.KeyValue {
+	frame, _ := o.frames(pcs).Next()
+	if frame.Function == "" {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		semconv.CodeFunctionKey.String(frame.Function),
+		semconv.CodeFilepathKey.String(frame.File),
+		semconv.CodeLineNumberKey.Int(frame.Line),
+	}
+}
+
+// formatStackTrace formats the frames from the first one that does not belong to a skipped package, like a panic does.
+func (o *callerOptions) formatStackTrace(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := o.frames(pcs)
+
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+
+		if !more {
+			return sb.String()
+		}
+	}
+}
+
+// inPackage checks whether the function belongs to the package or to one of its sub packages.
+func inPackage(function, pkg string) bool {
+	if !strings.HasPrefix(function, pkg) || len(function) == len(pkg) {
+		return false
+	}
+
+	c := function[len(pkg)]
+
+	return c == '.' || c == '/'
+}
+
+type callerOptionFunc func(o *callerOptions)
+
+func (f callerOptionFunc) applyCallerOptions(o *callerOptions) {
+	f(o)
+}
+
+// SkipCallerPackages skips the frames of the packages, and of their sub packages, to find the caller, for example the
+// ORMs or the repositories that wrap database/sql. The frames of database/sql, otelsql and the runtime are always
+// skipped.
+//
+//	otelsql.TraceCaller(otelsql.SkipCallerPackages("github.com/jmoiron/sqlx", "gorm.io"))
+func SkipCallerPackages(pkgs ...string) CallerOption {
+	return callerOptionFunc(func(o *callerOptions) {
+		o.skipPackages = append(o.skipPackages, pkgs...)
+	})
+}
+
+// CallerStackTrace records the exception.stacktrace of the errors on the spans of the failed calls.
+func CallerStackTrace() CallerOption {
+	return callerOptionFunc(func(o *callerOptions) {
+		o.stackTrace = true
+	})
+}
+
+// CallerMethods only captures the caller of the given traced methods, for example "query" and "exec", to keep the cost
+// acceptable. The caller of all the traced methods is captured by default.
+func CallerMethods(methods ...string) CallerOption {
+	return callerOptionFunc(func(o *callerOptions) {
+		o.methods = append(o.methods, methods...)
+	})
+}
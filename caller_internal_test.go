@@ -0,0 +1,61 @@
+package otelsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInPackage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		function string
+		pkg      string
+		expected bool
+	}{
+		{function: "database/sql.(*DB).QueryContext", pkg: "database/sql", expected: true},
+		{function: "go.nhat.io/otelsql.execStats.func1.1", pkg: "go.nhat.io/otelsql", expected: true},
+		{function: "go.nhat.io/otelsql/internal/test/oteltest.(*Suite).Run", pkg: "go.nhat.io/otelsql", expected: true},
+		{function: "go.nhat.io/otelsql_test.TestDriver", pkg: "go.nhat.io/otelsql", expected: false},
+		{function: "github.com/jmoiron/sqlxyz.Select", pkg: "github.com/jmoiron/sqlx", expected: false},
+		{function: "database/sql", pkg: "database/sql", expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.function, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, inPackage(tc.function, tc.pkg))
+		})
+	}
+}
+
+func TestCallerOptions_Captures(t *testing.T) {
+	t.Parallel()
+
+	var disabled *callerOptions
+
+	assert.False(t, disabled.captures("query"))
+	assert.True(t, (&callerOptions{}).captures("query"))
+
+	o := callerOptions{}
+	CallerMethods("query", "exec").applyCallerOptions(&o)
+
+	assert.True(t, o.captures("query"))
+	assert.True(t, o.captures("exec"))
+	assert.False(t, o.captures("commit"))
+}
+
+func TestCallerOptions_Skips(t *testing.T) {
+	t.Parallel()
+
+	o := callerOptions{}
+	SkipCallerPackages("gorm.io").applyCallerOptions(&o)
+
+	assert.True(t, o.skips("database/sql.(*DB).ExecContext"))
+	assert.True(t, o.skips("runtime.goexit"))
+	assert.True(t, o.skips("gorm.io/gorm.(*DB).Find"))
+	assert.False(t, o.skips("main.main"))
+}
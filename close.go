@@ -0,0 +1,103 @@
+package otelsql
+
+import (
+	"context"
+)
+
+const (
+	metricMethodConnClose = "go.sql.conn.close"
+	traceMethodConnClose  = "close_connection"
+	metricMethodStmtClose = "go.sql.stmt.close"
+	traceMethodStmtClose  = "close_statement"
+)
+
+// closeFuncMiddleware is a type for closeFunc middleware.
+type closeFuncMiddleware = middleware[closeFunc]
+
+// closeFunc is a callback for closeFunc.
+type closeFunc func(ctx context.Context) error
+
+// ensureClose converts a Close() method to a closeFunc.
+func ensureClose(f func() error) closeFunc {
+	return func(context.Context) error {
+		return f()
+	}
+}
+
+// closeStats records close stats.
+func closeStats(r methodRecorder, method string) closeFuncMiddleware {
+	return func(next closeFunc) closeFunc {
+		return func(ctx context.Context) (err error) {
+			end := r.Record(ctx, method)
+
+			defer func() {
+				end(err)
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// closeTrace traces close.
+func closeTrace(t methodTracer, method string) closeFuncMiddleware {
+	return func(next closeFunc) closeFunc {
+		return func(ctx context.Context) (err error) {
+			ctx, end := t.Trace(ctx, method)
+
+			defer func() {
+				end(err)
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// connCloseRecordLifetime records the lifetime and the use count of the connection when it is closed.
+func connCloseRecordLifetime(r connRecorder) closeFuncMiddleware {
+	return func(next closeFunc) closeFunc {
+		return func(ctx context.Context) error {
+			if s := connStateFromContext(ctx); s != nil {
+				defer r.RecordClose(ctx, s)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func makeConnCloseFuncMiddlewares(r methodRecorder, cr connRecorder, t methodTracer) []closeFuncMiddleware {
+	middlewares := make([]closeFuncMiddleware, 0, 3)
+	middlewares = append(middlewares, closeStats(r, metricMethodConnClose), connCloseRecordLifetime(cr))
+
+	if t != nil {
+		middlewares = append(middlewares, closeTrace(t, traceMethodConnClose))
+	}
+
+	return middlewares
+}
+
+// stmtCloseRecordLifetime records the lifetime and the executions of the statement when it is closed.
+func stmtCloseRecordLifetime(r stmtRecorder) closeFuncMiddleware {
+	return func(next closeFunc) closeFunc {
+		return func(ctx context.Context) error {
+			if s := stmtStateFromContext(ctx); s != nil {
+				defer r.RecordClose(ctx, s)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func makeStmtCloseFuncMiddlewares(r methodRecorder, sr stmtRecorder, t methodTracer) []closeFuncMiddleware {
+	middlewares := make([]closeFuncMiddleware, 0, 3)
+	middlewares = append(middlewares, closeStats(r, metricMethodStmtClose), stmtCloseRecordLifetime(sr))
+
+	if t != nil {
+		middlewares = append(middlewares, closeTrace(t, traceMethodStmtClose))
+	}
+
+	return middlewares
+}
@@ -0,0 +1,111 @@
+package otelsql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+
+	"go.nhat.io/otelsql/internal/test/oteltest"
+)
+
+func TestEnsureClose(t *testing.T) {
+	t.Parallel()
+
+	f := ensureClose(func() error {
+		return errors.New("close error")
+	})
+
+	err := f(context.Background())
+
+	require.EqualError(t, err, "close error")
+}
+
+func TestCloseStats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		method   string
+		close    closeFunc
+		expected string
+	}{
+		{
+			scenario: "connection with error",
+			method:   metricMethodConnClose,
+			close: func(context.Context) error {
+				return errors.New("error")
+			},
+			expected: `[
+				{
+					"Name": "db.sql.client.calls{service.name=otelsql,instrumentation.name=close_test,db.instance=test,db.operation=go.sql.conn.close,db.sql.error=error,db.sql.status=ERROR,db.system=other_sql}",
+					"Sum": 1
+				},
+				{
+					"Name": "db.sql.client.latency{service.name=otelsql,instrumentation.name=close_test,db.instance=test,db.operation=go.sql.conn.close,db.sql.error=error,db.sql.status=ERROR,db.system=other_sql}",
+					"Sum": "<ignore-diff>",
+					"Count": 1
+				}
+			]`,
+		},
+		{
+			scenario: "statement without error",
+			method:   metricMethodStmtClose,
+			close:    ensureClose(func() error { return nil }),
+			expected: `[
+				{
+					"Name": "db.sql.client.calls{service.name=otelsql,instrumentation.name=close_test,db.instance=test,db.operation=go.sql.stmt.close,db.sql.status=OK,db.system=other_sql}",
+					"Sum": 1
+				},
+				{
+					"Name": "db.sql.client.latency{service.name=otelsql,instrumentation.name=close_test,db.instance=test,db.operation=go.sql.stmt.close,db.sql.status=OK,db.system=other_sql}",
+					"Sum": "<ignore-diff>",
+					"Count": 1
+				}
+			]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			oteltest.New(oteltest.MetricsEqualJSON(tc.expected)).
+				Run(t, func(s oteltest.SuiteContext) {
+					meter := s.MeterProvider().Meter("close_test")
+
+					histogram, err := meter.Float64Histogram(dbSQLClientLatencyMs)
+					require.NoError(t, err)
+
+					count, err := meter.Int64Counter(dbSQLClientCalls)
+					require.NoError(t, err)
+
+					r := newMethodRecorder(histogram.Record, count.Add, nil,
+						semconv.DBSystemOtherSQL,
+						dbInstance.String("test"),
+					)
+
+					closeFn := chainMiddlewares([]closeFuncMiddleware{
+						closeStats(r, tc.method),
+					}, tc.close)
+
+					_ = closeFn(context.Background()) // nolint: errcheck
+				})
+		})
+	}
+}
+
+func TestMakeConnCloseFuncMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	assert.Len(t, makeConnCloseFuncMiddlewares(nil, r, nil), 2)
+	assert.Len(t, makeConnCloseFuncMiddlewares(nil, r, &methodTracerImpl{}), 3)
+	assert.Len(t, makeStmtCloseFuncMiddlewares(nil, nil, nil), 2)
+	assert.Len(t, makeStmtCloseFuncMiddlewares(nil, nil, &methodTracerImpl{}), 3)
+}
@@ -0,0 +1,143 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConformance_Conn(t *testing.T) {
+	t.Parallel()
+
+	optionals := []reflect.Type{
+		reflect.TypeFor[driver.Pinger](),
+		reflect.TypeFor[driver.ExecerContext](),
+		reflect.TypeFor[driver.QueryerContext](),
+		reflect.TypeFor[driver.NamedValueChecker](),
+		reflect.TypeFor[driver.SessionResetter](),
+		reflect.TypeFor[driver.Validator](),
+	}
+
+	// database/sql emulates these when the driver does not implement them, the wrapper always does the same so that the
+	// context is never lost.
+	always := []reflect.Type{
+		reflect.TypeFor[driver.ConnPrepareContext](),
+		reflect.TypeFor[driver.ConnBeginTx](),
+	}
+
+	for mask := range 1 << len(optionals) {
+		t.Run(fmt.Sprintf("%06b", mask), func(t *testing.T) {
+			t.Parallel()
+
+			parent := combineConn(conn{},
+				pick[driver.Pinger](mask, 0, pingFunc(nopPing)),
+				pick[driver.ExecerContext](mask, 1, execContextFunc(nopExecContext)),
+				pick[driver.QueryerContext](mask, 2, queryContextFunc(nopQueryContext)),
+				pick[driver.NamedValueChecker](mask, 3, connNamedValueChecker(nil)),
+				pick[driver.SessionResetter](mask, 4, connSessionResetter(nil)),
+				pick[driver.Validator](mask, 5, validatorFunc(nil)),
+			)
+
+			wrapped := wrapConn(parent, connConfig{})
+
+			assertConformance(t, mask, optionals, parent, wrapped)
+
+			for _, iface := range always {
+				assert.True(t, reflect.TypeOf(wrapped).Implements(iface), "wrapped conn does not implement %s", iface)
+			}
+		})
+	}
+}
+
+func TestConformance_Stmt(t *testing.T) {
+	t.Parallel()
+
+	optionals := []reflect.Type{
+		reflect.TypeFor[driver.StmtExecContext](),
+		reflect.TypeFor[driver.StmtQueryContext](),
+		reflect.TypeFor[columnConverter](),
+		reflect.TypeFor[driver.NamedValueChecker](),
+	}
+
+	for mask := range 1 << len(optionals) {
+		t.Run(fmt.Sprintf("%04b", mask), func(t *testing.T) {
+			t.Parallel()
+
+			parent := combineStmt(stmt{},
+				pick[driver.StmtExecContext](mask, 0, stmtExecContextFunc(nil)),
+				pick[driver.StmtQueryContext](mask, 1, stmtQueryContextFunc(nil)),
+				pick[columnConverter](mask, 2, stmtColumnConverterFunc(nil)),
+				pick[driver.NamedValueChecker](mask, 3, stmtNamedValueChecker(nil)),
+			)
+
+			wrapped := wrapStmt(parent, stmtConfig{})
+
+			assertConformance(t, mask, optionals, parent, wrapped)
+		})
+	}
+}
+
+func TestConformance_Rows(t *testing.T) {
+	t.Parallel()
+
+	optionals := []reflect.Type{
+		reflect.TypeFor[driver.RowsNextResultSet](),
+		reflect.TypeFor[driver.RowsColumnTypeDatabaseTypeName](),
+		reflect.TypeFor[driver.RowsColumnTypeLength](),
+		reflect.TypeFor[driver.RowsColumnTypeNullable](),
+		reflect.TypeFor[driver.RowsColumnTypePrecisionScale](),
+		reflect.TypeFor[driver.RowsColumnTypeScanType](),
+	}
+
+	nextResultSet := struct {
+		rowsHasNextResultSetFunc
+		rowsNextResultSetFunc
+	}{}
+
+	for mask := range 1 << len(optionals) {
+		t.Run(fmt.Sprintf("%06b", mask), func(t *testing.T) {
+			t.Parallel()
+
+			parent := combineRows(rows{},
+				pick[withRowsNextResultSet](mask, 0, nextResultSet),
+				pick[withRowsColumnTypeDatabaseTypeName](mask, 1, rowsColumnTypeDatabaseTypeNameFunc(nil)),
+				pick[withRowsColumnTypeLength](mask, 2, rowsColumnTypeLengthFunc(nil)),
+				pick[withRowsColumnTypeNullable](mask, 3, rowsColumnTypeNullableFunc(nil)),
+				pick[withRowsColumnTypePrecisionScale](mask, 4, rowsColumnTypePrecisionScaleFunc(nil)),
+				pick[withRowsColumnTypeScanType](mask, 5, rowsColumnTypeScanTypeFunc(nil)),
+			)
+
+			wrapped := wrapRows(context.Background(), parent, newMethodTracer(nil), true, true)
+
+			assertConformance(t, mask, optionals, parent, wrapped)
+		})
+	}
+}
+
+// pick returns v if the bit is set in the mask, nil otherwise.
+func pick[T any](mask, bit int, v T) T {
+	if mask&(1<<bit) != 0 {
+		return v
+	}
+
+	var zero T
+
+	return zero
+}
+
+func assertConformance(t *testing.T, mask int, optionals []reflect.Type, parent, wrapped any) {
+	t.Helper()
+
+	parentType, wrappedType := reflect.TypeOf(parent), reflect.TypeOf(wrapped)
+
+	for bit, iface := range optionals {
+		expected := mask&(1<<bit) != 0
+
+		assert.Equal(t, expected, parentType.Implements(iface), "parent implements %s", iface)
+		assert.Equal(t, expected, wrappedType.Implements(iface), "wrapped implements %s", iface)
+	}
+}
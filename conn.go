@@ -3,47 +3,126 @@ package otelsql
 import (
 	"context"
 	"database/sql/driver"
-	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	xattr "go.nhat.io/otelsql/attribute"
 )
 
+// connSequence generates the ids of the wrapped connections.
+var connSequence atomic.Int64
+
 type connConfig struct {
 	pingFuncMiddlewares         []pingFuncMiddleware
 	execContextFuncMiddlewares  []execContextFuncMiddleware
 	queryContextFuncMiddlewares []queryContextFuncMiddleware
 	beginFuncMiddlewares        []beginFuncMiddleware
 	prepareFuncMiddlewares      []prepareContextFuncMiddleware
+	closeFuncMiddlewares        []closeFuncMiddleware
+	resetSessionFuncMiddlewares []resetSessionFuncMiddleware
+	isValidFuncMiddlewares      []isValidFuncMiddleware
+
+	connRecorder connRecorder
 }
 
-type conn struct {
-	ping    pingFunc
-	exec    execContextFunc
-	query   queryContextFunc
-	begin   beginFunc
-	prepare prepareContextFunc
+// connState holds the identity and the usage of a wrapped connection.
+type connState struct {
+	id        int64
+	createdAt time.Time
+	uses      atomic.Int64
+	// waitTime is the time the caller waited for the connection, it is reported once on the following span, see
+	// takeWaitTime.
+	waitTime atomic.Int64
+	// inTx is true from a successful begin until the commit or the rollback of the transaction.
+	inTx atomic.Bool
 
-	close func() error
+	recorder connRecorder
 }
 
-func (c conn) Ping(ctx context.Context) error {
-	return c.ping(ctx)
+func newConnState() *connState {
+	return &connState{
+		id:        connSequence.Add(1),
+		createdAt: time.Now(),
+	}
 }
 
-// Deprecated: Drivers should implement ExecerContext instead.
-func (c conn) Exec(string, []driver.Value) (driver.Result, error) {
-	return nil, errors.New("otelsql: Exec is deprecated")
+// context attaches the connection state to the context.
+func (s *connState) context(ctx context.Context) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	// The connection is handed out to the call that marked its start, see ContextWithAcquireStart.
+	if start, ok := takeAcquireStart(ctx); ok {
+		s.wait(ctx, time.Since(start))
+	}
+
+	return context.WithValue(ctx, connStateCtxKey{}, s)
 }
 
-func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
-	return c.exec(ctx, query, args)
+// use attaches the connection state to the context of a call that uses the connection. The use is only counted by
+// countUse once the call has returned, but the spans of the call already include it in the use count.
+func (s *connState) use(ctx context.Context) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	return context.WithValue(s.context(ctx), connUseCtxKey{}, s)
 }
 
-// Deprecated: Drivers should implement QueryerContext instead.
-func (c conn) Query(string, []driver.Value) (driver.Rows, error) {
-	return nil, errors.New("otelsql: Query is deprecated")
+// countUse increases the use count of the connection. The wait time belongs to the first use of the connection after
+// it is handed out, the following uses do not inherit it.
+func (s *connState) countUse() {
+	if s != nil {
+		s.uses.Add(1)
+		s.waitTime.Store(0)
+	}
 }
 
-func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
-	return c.query(ctx, query, args)
+func (s *connState) attributes(ctx context.Context) []attribute.KeyValue {
+	uses := s.uses.Load()
+
+	if u, ok := ctx.Value(connUseCtxKey{}).(*connState); ok && u == s {
+		uses++
+	}
+
+	return []attribute.KeyValue{
+		dbClientConnectionID.Int64(s.id),
+		xattr.KeyValueDuration(dbClientConnectionAge, time.Since(s.createdAt)),
+		dbClientConnectionUseCount.Int64(uses),
+	}
+}
+
+// wait records the time the caller waited for the connection, and keeps it for the following span.
+func (s *connState) wait(ctx context.Context, waitTime time.Duration) {
+	s.waitTime.Store(int64(waitTime))
+
+	if s.recorder != nil {
+		s.recorder.RecordWaitTime(ctx, waitTime)
+	}
+}
+
+// takeWaitTime returns the attribute of the time the caller waited for the connection, only for the first span after
+// the connection is handed out.
+func (s *connState) takeWaitTime() (attribute.KeyValue, bool) {
+	waitTime := time.Duration(s.waitTime.Swap(0))
+	if waitTime <= 0 {
+		return attribute.KeyValue{}, false
+	}
+
+	return xattr.KeyValueDuration(dbClientConnectionWaitTimeKey, waitTime), true
+}
+
+type conn struct {
+	parent driver.Conn
+	state  *connState
+
+	begin   beginFunc
+	prepare prepareContextFunc
+
+	close closeFunc
 }
 
 func (c conn) Prepare(query string) (driver.Stmt, error) {
@@ -63,65 +142,121 @@ func (c conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, er
 }
 
 func (c conn) Close() error {
-	return c.close()
+	return c.close(c.state.context(context.Background()))
+}
+
+// Unwrap returns the underlying driver.Conn.
+func (c conn) Unwrap() driver.Conn {
+	return c.parent
+}
+
+func wrapConn(parent driver.Conn, cfg connConfig) driver.Conn {
+	state := newConnState()
+	state.recorder = cfg.connRecorder
+
+	return wrapConnWithState(parent, cfg, state)
 }
 
-func wrapConn(parent driver.Conn, opt connConfig) driver.Conn {
-	c := makeConn(parent, opt)
+func wrapConnWithState(parent driver.Conn, cfg connConfig, state *connState) driver.Conn {
+	c := makeConn(parent, cfg, state)
 
 	var (
-		n, hasNameValueChecker = parent.(driver.NamedValueChecker)
-		s, hasSessionResetter  = parent.(driver.SessionResetter)
+		p driver.Pinger
+		e driver.ExecerContext
+		q driver.QueryerContext
+		s driver.SessionResetter
+		v driver.Validator
 	)
 
-	switch {
-	default:
-		// case !hasNameValueChecker && !hasSessionResetter:
-		return c
+	if pinger, ok := parent.(driver.Pinger); ok {
+		p = makeConnPinger(c.state, pinger, cfg)
+	}
 
-	case hasNameValueChecker && !hasSessionResetter:
-		return struct {
-			conn
-			driver.NamedValueChecker
-		}{c, n}
+	if execer, ok := parent.(driver.ExecerContext); ok {
+		e = connExecContext(c.state, chainMiddlewares(cfg.execContextFuncMiddlewares, execer.ExecContext))
+	}
 
-	case !hasNameValueChecker && hasSessionResetter:
-		return struct {
-			conn
-			driver.SessionResetter
-		}{c, s}
+	if queryer, ok := parent.(driver.QueryerContext); ok {
+		q = connQueryContext(c.state, chainMiddlewares(cfg.queryContextFuncMiddlewares, queryer.QueryContext))
+	}
 
-	case hasNameValueChecker && hasSessionResetter:
-		return struct {
-			conn
-			driver.NamedValueChecker
-			driver.SessionResetter
-		}{c, n, s}
+	if resetter, ok := parent.(driver.SessionResetter); ok {
+		s = makeSessionResetter(c.state, resetter, cfg)
 	}
-}
 
-func makeConn(parent driver.Conn, cfg connConfig) conn {
-	c := conn{
-		ping:  nopPing,
-		exec:  skippedExecContext,
-		query: skippedQueryContext,
-		close: parent.Close,
+	if validator, ok := parent.(driver.Validator); ok {
+		v = makeValidator(c.state, validator, cfg)
 	}
 
-	if p, ok := parent.(driver.Pinger); ok {
-		c.ping = chainMiddlewares(cfg.pingFuncMiddlewares, p.Ping)
+	n, _ := parent.(driver.NamedValueChecker) //nolint: errcheck
+
+	return combineConn(c, p, e, q, n, s, v)
+}
+
+func makeConn(parent driver.Conn, cfg connConfig, state *connState) conn {
+	begin := chainMiddlewares(cfg.beginFuncMiddlewares, ensureBegin(parent))
+	prepare := chainMiddlewares(cfg.prepareFuncMiddlewares, ensurePrepareContext(parent))
+
+	return conn{
+		parent: parent,
+		state:  state,
+		begin: func(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+			return begin(state.context(ctx), opts)
+		},
+		prepare: func(ctx context.Context, query string) (driver.Stmt, error) {
+			return prepare(state.context(ctx), query)
+		},
+		close: chainMiddlewares(cfg.closeFuncMiddlewares, ensureClose(parent.Close)),
 	}
+}
+
+func makeConnPinger(state *connState, parent driver.Pinger, cfg connConfig) driver.Pinger {
+	ping := chainMiddlewares(cfg.pingFuncMiddlewares, parent.Ping)
+
+	return pingFunc(func(ctx context.Context) error {
+		return ping(state.context(ctx))
+	})
+}
+
+// connExecContext counts the use of the connection after executing, unless the exec is skipped.
+func connExecContext(s *connState, next execContextFunc) execContextFunc {
+	return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+		result, err := next(s.use(ctx), query, args)
 
-	if p, ok := parent.(driver.ExecerContext); ok {
-		c.exec = chainMiddlewares(cfg.execContextFuncMiddlewares, p.ExecContext)
+		observeUnlessSkipped(err, s.countUse)
+
+		return result, err
 	}
+}
 
-	if p, ok := parent.(driver.QueryerContext); ok {
-		c.query = chainMiddlewares(cfg.queryContextFuncMiddlewares, p.QueryContext)
+// connQueryContext counts the use of the connection after querying, unless the query is skipped.
+func connQueryContext(s *connState, next queryContextFunc) queryContextFunc {
+	return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+		rows, err := next(s.use(ctx), query, args)
+
+		observeUnlessSkipped(err, s.countUse)
+
+		return rows, err
 	}
+}
+
+func makeSessionResetter(state *connState, parent driver.SessionResetter, cfg connConfig) driver.SessionResetter {
+	reset := chainMiddlewares(cfg.resetSessionFuncMiddlewares, parent.ResetSession)
+
+	return resetSessionFunc(func(ctx context.Context) error {
+		// The connection is handed out from the pool, the wait time of the previous caller is not relevant anymore.
+		state.waitTime.Store(0)
+
+		return reset(state.context(ctx))
+	})
+}
 
-	c.begin = chainMiddlewares(cfg.beginFuncMiddlewares, ensureBegin(parent))
-	c.prepare = chainMiddlewares(cfg.prepareFuncMiddlewares, ensurePrepareContext(parent))
+func makeValidator(state *connState, parent driver.Validator, cfg connConfig) driver.Validator {
+	isValid := chainMiddlewares(cfg.isValidFuncMiddlewares, func(context.Context) bool {
+		return parent.IsValid()
+	})
 
-	return c
+	return validatorFunc(func() bool {
+		return isValid(state.context(context.Background()))
+	})
 }
@@ -5,31 +5,12 @@ import (
 	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestConn_Exec(t *testing.T) {
-	t.Parallel()
-
-	c := conn{}
-	result, err := c.Exec("", nil)
-
-	assert.Nil(t, result)
-	assert.Error(t, err)
-}
-
-func TestConn_Query(t *testing.T) {
-	t.Parallel()
-
-	c := conn{}
-	rows, err := c.Query("", nil)
-
-	assert.Nil(t, rows)
-	assert.Error(t, err)
-}
-
 func TestConn_Prepare(t *testing.T) {
 	t.Parallel()
 
@@ -213,6 +194,79 @@ func TestWrapConn(t *testing.T) {
 	}
 }
 
+func TestWrapConn_WithoutPingExecQuery(t *testing.T) {
+	t.Parallel()
+
+	parent := struct {
+		connPrepareFunc
+		connCloseFunc
+		connBeginFunc
+	}{}
+
+	conn := wrapConn(parent, connConfig{})
+
+	// database/sql falls back to Prepare when the conn does not implement ExecerContext or QueryerContext, and skips
+	// the ping when it does not implement Pinger.
+	assert.NotImplements(t, (*driver.Pinger)(nil), conn)
+	assert.NotImplements(t, (*driver.ExecerContext)(nil), conn)
+	assert.NotImplements(t, (*driver.QueryerContext)(nil), conn)
+	assert.NotImplements(t, (*driver.Execer)(nil), conn)  //nolint: staticcheck
+	assert.NotImplements(t, (*driver.Queryer)(nil), conn) //nolint: staticcheck
+}
+
+func TestWrapConn_WithPingExecQuery(t *testing.T) {
+	t.Parallel()
+
+	var (
+		expectedPingError  = errors.New("ping error")
+		expectedExecError  = errors.New("exec error")
+		expectedQueryError = errors.New("query error")
+	)
+
+	parent := struct {
+		connPrepareFunc
+		connCloseFunc
+		connBeginFunc
+		pingFunc
+		execContextFunc
+		queryContextFunc
+	}{
+		pingFunc: func(context.Context) error {
+			return expectedPingError
+		},
+		execContextFunc: func(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+			assert.Equal(t, "DELETE FROM data", query)
+
+			return nil, expectedExecError
+		},
+		queryContextFunc: func(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+			assert.Equal(t, "SELECT * FROM data", query)
+
+			return nil, expectedQueryError
+		},
+	}
+
+	conn := wrapConn(parent, connConfig{})
+
+	require.Implements(t, (*driver.Pinger)(nil), conn)
+	require.Implements(t, (*driver.ExecerContext)(nil), conn)
+	require.Implements(t, (*driver.QueryerContext)(nil), conn)
+
+	err := conn.(driver.Pinger).Ping(context.Background())
+
+	require.ErrorIs(t, err, expectedPingError)
+
+	result, err := conn.(driver.ExecerContext).ExecContext(context.Background(), "DELETE FROM data", nil)
+
+	assert.Nil(t, result)
+	require.ErrorIs(t, err, expectedExecError)
+
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), "SELECT * FROM data", nil)
+
+	assert.Nil(t, rows)
+	require.ErrorIs(t, err, expectedQueryError)
+}
+
 type connPrepareFunc func(query string) (driver.Stmt, error)
 
 func (f connPrepareFunc) Prepare(query string) (driver.Stmt, error) {
@@ -242,3 +296,142 @@ type connSessionResetter func(ctx context.Context) error
 func (f connSessionResetter) ResetSession(ctx context.Context) error {
 	return f(ctx)
 }
+
+func TestConnState_Use(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+
+	ctx := s.use(context.Background())
+
+	assert.Same(t, s, connStateFromContext(ctx))
+	assert.Equal(t, int64(0), s.uses.Load())
+
+	// The call that uses the connection is included in the use count of its spans.
+	assert.Contains(t, s.attributes(ctx), dbClientConnectionUseCount.Int64(1))
+
+	s.countUse()
+
+	ctx = s.context(context.Background())
+
+	assert.Same(t, s, connStateFromContext(ctx))
+	assert.Equal(t, int64(1), s.uses.Load())
+	assert.Contains(t, s.attributes(ctx), dbClientConnectionUseCount.Int64(1))
+}
+
+func TestConnExecContext_ErrSkip(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+
+	skipped := connExecContext(s, func(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+		return nil, driver.ErrSkip
+	})
+
+	_, err := skipped(context.Background(), "", nil)
+
+	require.ErrorIs(t, err, driver.ErrSkip)
+	assert.Equal(t, int64(0), s.uses.Load(), "skipped execs must not be counted")
+
+	_, err = connExecContext(s, nopExecContext)(context.Background(), "", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), s.uses.Load())
+}
+
+func TestConnQueryContext_ErrSkip(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+
+	skipped := connQueryContext(s, func(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+		return nil, driver.ErrSkip
+	})
+
+	_, err := skipped(context.Background(), "", nil)
+
+	require.ErrorIs(t, err, driver.ErrSkip)
+	assert.Equal(t, int64(0), s.uses.Load(), "skipped queries must not be counted")
+
+	_, err = connQueryContext(s, nopQueryContext)(context.Background(), "", nil) //nolint: sqlclosecheck
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), s.uses.Load())
+}
+
+func TestConnState_CountUse_ClearWaitTime(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+	s.waitTime.Store(int64(time.Second))
+
+	s.countUse()
+
+	// The following uses of the connection do not inherit the wait time of the first one.
+	_, ok := s.takeWaitTime()
+
+	assert.False(t, ok)
+}
+
+func TestConnState_Nil(t *testing.T) {
+	t.Parallel()
+
+	var s *connState
+
+	ctx := s.use(context.Background())
+
+	assert.Nil(t, connStateFromContext(ctx))
+	assert.NotPanics(t, s.countUse)
+}
+
+func TestConnState_UniqueID(t *testing.T) {
+	t.Parallel()
+
+	first := newConnState()
+	second := newConnState()
+
+	assert.NotEqual(t, first.id, second.id)
+}
+
+func TestConnCloseRecordLifetime(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+	r := &connRecorderFunc{}
+
+	s.countUse()
+	s.countUse()
+
+	closeConn := chainMiddlewares([]closeFuncMiddleware{connCloseRecordLifetime(r)}, func(context.Context) error {
+		return errors.New("close error")
+	})
+
+	err := closeConn(s.context(context.Background()))
+
+	require.EqualError(t, err, "close error")
+	assert.Same(t, s, r.state)
+	assert.Equal(t, int64(2), r.state.uses.Load())
+}
+
+type connRecorderFunc struct {
+	state       *connState
+	discards    []string
+	waitTimes   []time.Duration
+	createTimes []time.Duration
+}
+
+func (r *connRecorderFunc) RecordClose(_ context.Context, s *connState) {
+	r.state = s
+}
+
+func (r *connRecorderFunc) RecordDiscard(_ context.Context, reason string) {
+	r.discards = append(r.discards, reason)
+}
+
+func (r *connRecorderFunc) RecordWaitTime(_ context.Context, waitTime time.Duration) {
+	r.waitTimes = append(r.waitTimes, waitTime)
+}
+
+func (r *connRecorderFunc) RecordCreateTime(_ context.Context, createTime time.Duration) {
+	r.createTimes = append(r.createTimes, createTime)
+}
@@ -0,0 +1,67 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+	"time"
+)
+
+// connectFunc opens a new connection.
+type connectFunc func(ctx context.Context) (driver.Conn, error)
+
+// acquireStart is the start of a database/sql call, see ContextWithAcquireStart.
+type acquireStart struct {
+	time  time.Time
+	taken atomic.Bool
+}
+
+// ContextWithAcquireStart marks the start of a database/sql call in the context, so that the time the call waits for a
+// connection is recorded, even when the connection is taken from the pool.
+//
+// database/sql does not tell the driver when a call starts, the driver only sees the connection once it is handed out.
+// The wait is measured the first time the call uses a connection, so the context must be marked right before each call:
+//
+//	rows, err := db.QueryContext(otelsql.ContextWithAcquireStart(ctx), query)
+//
+// Without the mark, the wait is not known and not recorded, only the time it took to create the new connections is.
+func ContextWithAcquireStart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, acquireStartCtxKey{}, &acquireStart{time: time.Now()})
+}
+
+// takeAcquireStart returns the start of the call of the context, only the first time it is taken.
+func takeAcquireStart(ctx context.Context) (time.Time, bool) {
+	s, ok := ctx.Value(acquireStartCtxKey{}).(*acquireStart)
+	if !ok || !s.taken.CompareAndSwap(false, true) {
+		return time.Time{}, false
+	}
+
+	return s.time, true
+}
+
+// connect opens a new connection and wraps it.
+//
+// The duration of Connect() is the time it took to create the connection, not the time a caller waited for it:
+// database/sql also opens connections in the background, that no caller waits for. The wait is only recorded when the
+// start of the call is marked in the context, see ContextWithAcquireStart.
+func connect(ctx context.Context, parent connectFunc, cfg connConfig) (driver.Conn, error) {
+	start := time.Now()
+
+	c, err := parent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := newConnState()
+	state.recorder = cfg.connRecorder
+
+	if state.recorder != nil {
+		state.recorder.RecordCreateTime(ctx, time.Since(start))
+	}
+
+	if callStart, ok := takeAcquireStart(ctx); ok {
+		state.wait(ctx, time.Since(callStart))
+	}
+
+	return wrapConnWithState(c, cfg, state), nil
+}
@@ -0,0 +1,148 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	c, err := connect(context.Background(), func(context.Context) (driver.Conn, error) {
+		time.Sleep(time.Millisecond)
+
+		return unwrapConn{}, nil
+	}, connConfig{connRecorder: r})
+	require.NoError(t, err)
+
+	require.Len(t, r.createTimes, 1)
+	assert.GreaterOrEqual(t, r.createTimes[0], time.Millisecond)
+
+	// Without the start of the call, the time it took to create the connection is not a wait.
+	assert.Empty(t, r.waitTimes)
+
+	state := c.(conn).state //nolint: errcheck,forcetypeassert
+
+	// Reading the attributes does not change them.
+	assert.Len(t, state.attributes(context.Background()), 3)
+	assert.Len(t, state.attributes(context.Background()), 3)
+
+	_, ok := state.takeWaitTime()
+
+	assert.False(t, ok)
+}
+
+func TestConnect_AcquireStart(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+	ctx := ContextWithAcquireStart(context.Background())
+
+	time.Sleep(10 * time.Millisecond)
+
+	c, err := connect(ctx, func(context.Context) (driver.Conn, error) {
+		return unwrapConn{}, nil
+	}, connConfig{connRecorder: r})
+	require.NoError(t, err)
+
+	// The wait starts with the call, not with the connect.
+	require.Len(t, r.waitTimes, 1)
+	assert.GreaterOrEqual(t, r.waitTimes[0], 10*time.Millisecond)
+
+	require.Len(t, r.createTimes, 1)
+	assert.Less(t, r.createTimes[0], r.waitTimes[0])
+
+	// The wait of the call is measured once, and reported once, on the following span.
+	state := c.(conn).state //nolint: errcheck,forcetypeassert
+	state.context(ctx)
+
+	assert.Len(t, r.waitTimes, 1)
+
+	waitTime, ok := state.takeWaitTime()
+
+	assert.True(t, ok)
+	assert.Equal(t, dbClientConnectionWaitTimeKey.String(r.waitTimes[0].String()), waitTime)
+
+	_, ok = state.takeWaitTime()
+
+	assert.False(t, ok)
+}
+
+func TestConnect_Error(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	c, err := connect(context.Background(), func(context.Context) (driver.Conn, error) {
+		return nil, errors.New("connect error")
+	}, connConfig{connRecorder: r})
+
+	require.EqualError(t, err, "connect error")
+	assert.Nil(t, c)
+	assert.Empty(t, r.waitTimes)
+	assert.Empty(t, r.createTimes)
+}
+
+func TestConnState_AcquireStart(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	s := newConnState()
+	s.recorder = r
+
+	// Without the start of the call, the wait of a connection from the pool is not known.
+	s.context(context.Background())
+
+	assert.Empty(t, r.waitTimes)
+
+	ctx := ContextWithAcquireStart(context.Background())
+
+	time.Sleep(10 * time.Millisecond)
+
+	// The first use of the connection by the call measures the wait.
+	s.context(ctx)
+	s.context(ctx)
+
+	require.Len(t, r.waitTimes, 1)
+	assert.GreaterOrEqual(t, r.waitTimes[0], 10*time.Millisecond)
+
+	waitTime, ok := s.takeWaitTime()
+
+	assert.True(t, ok)
+	assert.Equal(t, dbClientConnectionWaitTimeKey.String(r.waitTimes[0].String()), waitTime)
+}
+
+func TestConnect_ResetSession(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	s := newConnState()
+	s.recorder = r
+	s.waitTime.Store(int64(time.Second))
+
+	reset := makeSessionResetter(s, connSessionResetter(func(context.Context) error {
+		return nil
+	}), connConfig{})
+
+	err := reset.ResetSession(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, s.waitTime.Load())
+
+	// The connection is handed out to a call that marked its start.
+	err = reset.ResetSession(ContextWithAcquireStart(context.Background()))
+	require.NoError(t, err)
+
+	assert.Len(t, r.waitTimes, 1)
+	assert.NotZero(t, s.waitTime.Load())
+}
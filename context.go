@@ -1,20 +1,178 @@
 package otelsql
 
-import "context"
+import (
+	"context"
+	"database/sql/driver"
+	"time"
 
-type queryCtxKey struct{}
+	"go.opentelemetry.io/otel/trace"
+)
+
+type queryInfoCtxKey struct{}
+
+type queryArgsCountCtxKey struct{}
+
+type operationInfoCtxKey struct{}
+
+type connStateCtxKey struct{}
+
+type connUseCtxKey struct{}
+
+type stmtStateCtxKey struct{}
+
+type startTimeCtxKey struct{}
+
+type acquireStartCtxKey struct{}
+
+// QueryInfo describes the query of a traced method. When the query is prepared or run by a prepared statement, it also
+// identifies the statement and its prepare.
+type QueryInfo struct {
+	// Query is the text of the query.
+	Query string
+	// StatementID identifies the prepared statement, it is zero if the query is not prepared.
+	StatementID int64
+	// PrepareSpan is the span context of the prepare of the statement, it is invalid if the prepare was not traced or
+	// if the query is not run by a prepared statement.
+	PrepareSpan trace.SpanContext
+}
+
+// QueryInfoFromContext gets the query info from context.
+func QueryInfoFromContext(ctx context.Context) (QueryInfo, bool) {
+	info, ok := ctx.Value(queryInfoCtxKey{}).(QueryInfo)
+
+	return info, ok
+}
+
+// ContextWithQueryInfo attaches the query info to the parent context.
+func ContextWithQueryInfo(ctx context.Context, info QueryInfo) context.Context {
+	return context.WithValue(ctx, queryInfoCtxKey{}, info)
+}
 
 // QueryFromContext gets the query from context.
 func QueryFromContext(ctx context.Context) string {
-	query, ok := ctx.Value(queryCtxKey{}).(string)
-	if !ok {
-		return ""
-	}
+	info, _ := QueryInfoFromContext(ctx)
 
-	return query
+	return info.Query
 }
 
 // ContextWithQuery attaches the query to the parent context.
 func ContextWithQuery(ctx context.Context, query string) context.Context {
-	return context.WithValue(ctx, queryCtxKey{}, query)
+	return ContextWithQueryInfo(ctx, QueryInfo{Query: query})
+}
+
+// OperationInfo describes a traced method. It is in the context given to the span name formatter, to the TraceQuery()
+// function and to the ConvertErrorToSpanStatusContext() function.
+type OperationInfo struct {
+	// Method is the traced method, for example "exec", "query" or "prepare".
+	Method string
+	// Query is the query of the method, it is empty if the method has no query.
+	Query string
+	// ArgsCount is the number of arguments of the query.
+	ArgsCount int
+	// InTransaction is true if the method runs on a connection that has an ongoing transaction.
+	InTransaction bool
+	// Prepared is true if the method runs on a prepared statement.
+	Prepared bool
+	// ConnectionID identifies the connection, it is zero if the method does not run on a connection.
+	ConnectionID int64
+	// StartTime is when the method started.
+	StartTime time.Time
+}
+
+// OperationInfoFromContext gets the operation info from context.
+func OperationInfoFromContext(ctx context.Context) (OperationInfo, bool) {
+	info, ok := ctx.Value(operationInfoCtxKey{}).(OperationInfo)
+
+	return info, ok
+}
+
+// contextWithOperation attaches the info of the traced method to the context.
+func contextWithOperation(ctx context.Context, method string) context.Context {
+	info := OperationInfo{
+		Method:    method,
+		Query:     QueryFromContext(ctx),
+		Prepared:  stmtStateFromContext(ctx) != nil,
+		StartTime: startTimeFromContext(ctx),
+	}
+
+	info.ArgsCount, _ = ctx.Value(queryArgsCountCtxKey{}).(int) //nolint: errcheck
+
+	if s := connStateFromContext(ctx); s != nil {
+		info.ConnectionID = s.id
+		info.InTransaction = s.inTx.Load()
+	}
+
+	return context.WithValue(ctx, operationInfoCtxKey{}, info)
+}
+
+// contextWithStartTime overrides the start time of the next traced method, for the spans that are created after the
+// fact.
+func contextWithStartTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, startTimeCtxKey{}, t)
+}
+
+// startTimeFromContext gets the start time of the traced method from context, it is now if it is not overridden.
+func startTimeFromContext(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(startTimeCtxKey{}).(time.Time); ok {
+		return t
+	}
+
+	return time.Now()
+}
+
+// contextWithOperationOf attaches the operation info of the other context to the context.
+func contextWithOperationOf(ctx context.Context, other context.Context) context.Context {
+	info, ok := OperationInfoFromContext(other)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, operationInfoCtxKey{}, info)
+}
+
+// contextWithQueryArgs attaches the number of arguments of the query to the context.
+func contextWithQueryArgs(ctx context.Context, args []driver.NamedValue) context.Context {
+	return context.WithValue(ctx, queryArgsCountCtxKey{}, len(args))
+}
+
+// contextWithQuery attaches the query to the context, unless the context already describes it, for example when the
+// query is run by a prepared statement.
+func contextWithQuery(ctx context.Context, query string) context.Context {
+	if info, ok := QueryInfoFromContext(ctx); ok && info.Query == query {
+		return ctx
+	}
+
+	return ContextWithQuery(ctx, query)
+}
+
+// connStateFromContext gets the state of the connection from context.
+func connStateFromContext(ctx context.Context) *connState {
+	s, ok := ctx.Value(connStateCtxKey{}).(*connState)
+	if !ok {
+		return nil
+	}
+
+	return s
+}
+
+// stmtStateFromContext gets the state of the prepared statement from context.
+func stmtStateFromContext(ctx context.Context) *stmtState {
+	s, ok := ctx.Value(stmtStateCtxKey{}).(*stmtState)
+	if !ok {
+		return nil
+	}
+
+	return s
+}
+
+// detachContext creates a new context.Background that keeps the span context, the connection state and the query budget
+// of the given context.
+func detachContext(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+
+	if b := queryBudgetFromContext(ctx); b != nil {
+		detached = context.WithValue(detached, queryBudgetCtxKey{}, b)
+	}
+
+	return connStateFromContext(ctx).context(detached)
 }
@@ -21,3 +21,24 @@ func TestQueryContext(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestQueryInfoContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := otelsql.QueryInfoFromContext(context.Background())
+	assert.False(t, ok)
+
+	expected := otelsql.QueryInfo{Query: "SELECT 1", StatementID: 42}
+
+	ctx := otelsql.ContextWithQueryInfo(context.Background(), expected)
+	actual, ok := otelsql.QueryInfoFromContext(ctx)
+
+	assert.True(t, ok)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "SELECT 1", otelsql.QueryFromContext(ctx))
+
+	actual, ok = otelsql.QueryInfoFromContext(otelsql.ContextWithQuery(context.Background(), "SELECT 2"))
+
+	assert.True(t, ok)
+	assert.Equal(t, otelsql.QueryInfo{Query: "SELECT 2"}, actual)
+}
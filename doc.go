@@ -1,2 +1,4 @@
 // Package otelsql provides traces and metrics for database/sql drivers.
 package otelsql
+
+//go:generate go run ./internal/cmd/wrapgen
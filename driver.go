@@ -6,8 +6,6 @@ import (
 	"database/sql/driver"
 	"errors"
 	"io"
-	"strconv"
-	"sync"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
@@ -15,26 +13,43 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-const _maxDriver = 150
-
 const instrumentationName = "go.nhat.io/otelsql"
 
 const (
 	dbSQLClientLatencyMs = "db.sql.client.latency"
 	dbSQLClientCalls     = "db.sql.client.calls"
 
+	dbClientConnectionLifetime   = "db.client.connection.lifetime"
+	dbClientConnectionUses       = "db.client.connection.uses"
+	dbClientConnectionDiscards   = "db.client.connection.discards"
+	dbClientConnectionWaitTime   = "db.client.connection.wait_time"
+	dbClientConnectionCreateTime = "db.client.connection.create_time"
+
+	dbClientResponseReturnedRows   = "db.client.response.returned_rows"
+	dbClientResponseReturnedBytes  = "db.client.response.returned_bytes"
+	dbClientResponseTimeToFirstRow = "db.client.response.time_to_first_row"
+	dbClientResponseDuration       = "db.client.response.duration"
+	dbClientRowsAffected           = "db.client.rows_affected"
+
+	dbClientStatementsOpen      = "db.client.statements.open"
+	dbClientStatementLifetime   = "db.client.statement.lifetime"
+	dbClientStatementExecutions = "db.client.statement.executions"
+	dbClientStatementPrepares   = "db.client.statement.prepares"
+
+	dbClientNPlusOne = "db.client.n_plus_one"
+
 	unitDimensionless = "1"
 	unitBytes         = "By"
 	unitMilliseconds  = "ms"
+	unitRow           = "{row}"
 )
 
-var regMu sync.Mutex
-
 // Register initializes and registers our otelsql wrapped database driver identified by its driverName and using provided
 // options. On success, it returns the generated driverName to use when calling sql.Open.
 //
 // It is possible to register multiple wrappers for the same database driver if needing different options for
-// different connections.
+// different connections. Registering the same driver with identical options returns the existing driver name, unless the
+// options contain closures, for example a custom span name formatter.
 func Register(driverName string, options ...DriverOption) (string, error) {
 	return RegisterWithSource(driverName, "", options...)
 }
@@ -46,7 +61,7 @@ func Register(driverName string, options ...DriverOption) (string, error) {
 // generated driverName to use when calling sql.Open.
 //
 // It is possible to register multiple wrappers for the same database driver if needing different options for
-// different connections.
+// different connections. See Register for the reuse of the driver names.
 func RegisterWithSource(driverName string, source string, options ...DriverOption) (string, error) {
 	// retrieve the driver implementation we need to wrap with instrumentation
 	db, err := sql.Open(driverName, source)
@@ -60,35 +75,53 @@ func RegisterWithSource(driverName string, source string, options ...DriverOptio
 		return "", err
 	}
 
-	regMu.Lock()
-	defer regMu.Unlock()
+	return register(driverName, dri, options)
+}
 
-	// Since we might want to register multiple otelsql drivers to have different options, but potentially the same
-	// underlying database driver, we cycle through to find available driver names.
-	driverName += "-otelsql-"
+// Wrap takes a SQL driver and wraps it with OpenTelemetry instrumentation.
+func Wrap(d driver.Driver, opts ...DriverOption) driver.Driver {
+	return wrapDriver(d, newConnConfig(newDriverOptions(opts...)))
+}
 
-	for i := int64(0); i < _maxDriver; i++ {
-		found := false
-		regName := driverName + strconv.FormatInt(i, 10)
+// WrapConnector takes a SQL driver connector and wraps it with OpenTelemetry instrumentation. Unlike Register, it does not
+// need to open a database to discover the driver nor register a new driver name.
+func WrapConnector(c driver.Connector, opts ...DriverOption) driver.Connector {
+	return wrapConnector(c, newConnConfig(newDriverOptions(opts...)))
+}
 
-		for _, name := range sql.Drivers() {
-			if name == regName {
-				found = true
-			}
-		}
+// OpenDB opens a database using the provided driver connector wrapped with OpenTelemetry instrumentation.
+//
+// If WithRecordStats() is used, OpenDB also records the database connection metrics with the same meter provider and
+// default attributes, until the database is closed.
+func OpenDB(c driver.Connector, opts ...DriverOption) (*sql.DB, error) {
+	o := newDriverOptions(opts...)
+	connector := wrapConnector(c, newConnConfig(o))
+
+	if !o.recordStats {
+		return sql.OpenDB(connector), nil
+	}
+
+	// The statistics are not recorded anymore when the database is closed.
+	sc := &statsConnector{otConnector: connector}
+	db := sql.OpenDB(sc)
 
-		if !found {
-			sql.Register(regName, Wrap(dri, options...))
+	statsOpts := make([]StatsOption, 0, len(o.statsOptions)+2)
+	statsOpts = append(statsOpts, WithMeterProvider(o.meterProvider), WithDefaultAttributes(o.defaultAttributes...))
+	statsOpts = append(statsOpts, o.statsOptions...)
 
-			return regName, nil
-		}
+	registration, err := RecordStatsWithRegistration(db, statsOpts...)
+	if err != nil {
+		_ = db.Close() // nolint: errcheck
+
+		return nil, err
 	}
 
-	return "", errors.New("unable to register driver, all slots have been taken")
+	sc.registration = registration
+
+	return db, nil
 }
 
-// Wrap takes a SQL driver and wraps it with OpenTelemetry instrumentation.
-func Wrap(d driver.Driver, opts ...DriverOption) driver.Driver {
+func newDriverOptions(opts ...DriverOption) driverOptions {
 	o := driverOptions{
 		meterProvider:  otel.GetMeterProvider(),
 		tracerProvider: otel.GetTracerProvider(),
@@ -102,24 +135,49 @@ func Wrap(d driver.Driver, opts ...DriverOption) driver.Driver {
 		option.applyDriverOptions(&o)
 	}
 
-	return wrapDriver(d, o)
+	return o
+}
+
+// wrappedDriver is a driver.Driver that gives access to the underlying driver.Driver.
+type wrappedDriver interface {
+	driver.Driver
+	io.Closer
+
+	Unwrap() driver.Driver
+}
+
+// driverWithContext is the driver.Driver returned by Wrap when the underlying driver.Driver is a driver.DriverContext.
+// Its Driver is the otDriver, which gives access to Close and Unwrap.
+type driverWithContext = struct {
+	driver.Driver
+	driver.DriverContext
 }
 
-func wrapDriver(d driver.Driver, o driverOptions) driver.Driver {
+func wrapDriver(d driver.Driver, cfg connConfig) driver.Driver {
 	drv := otDriver{
 		parent:     d,
-		connConfig: newConnConfig(o),
-		close:      func() error { return nil },
+		connConfig: cfg,
 	}
 
 	if _, ok := d.(driver.DriverContext); ok {
-		return struct {
-			driver.Driver
-			driver.DriverContext
-		}{drv, drv}
+		return driverWithContext{drv, drv}
 	}
 
-	return struct{ driver.Driver }{drv}
+	return struct{ wrappedDriver }{drv}
+}
+
+func wrapConnector(c driver.Connector, cfg connConfig) otConnector {
+	var drv driver.Driver
+
+	if d := c.Driver(); d != nil {
+		drv = wrapDriver(d, cfg)
+	}
+
+	return otConnector{
+		parent:     c,
+		driver:     drv,
+		connConfig: cfg,
+	}
 }
 
 func newConnConfig(opts driverOptions) connConfig {
@@ -132,7 +190,13 @@ func newConnConfig(opts driverOptions) connConfig {
 		traceWithAllowRoot(opts.trace.AllowRoot),
 		traceWithDefaultAttributes(opts.defaultAttributes...),
 		traceWithSpanNameFormatter(opts.trace.spanNameFormatter),
-		traceWithErrorToSpanStatus(opts.trace.errorToSpanStatus),
+		traceWithErrorToSpanStatus(opts.trace.errorToStatus()),
+		traceWithConnection(opts.trace.Connection),
+		traceWithStatement(opts.trace.Statement),
+		traceWithSQLErrors(opts.trace.SQLErrors),
+		traceWithIgnoredSQLErrors(opts.trace.ignoredSQLErrors),
+		traceWithErrorPolicies(opts.errorPolicies),
+		traceWithCaller(opts.trace.Caller, opts.trace.caller),
 	)
 
 	latencyMsHistogram, err := meter.Float64Histogram(dbSQLClientLatencyMs,
@@ -147,70 +211,227 @@ func newConnConfig(opts driverOptions) connConfig {
 	)
 	mustNoError(err)
 
-	latencyRecorder := newMethodRecorder(latencyMsHistogram.Record, callsCounter.Add, opts.defaultAttributes...)
+	connLifetimeHistogram, err := meter.Float64Histogram(dbClientConnectionLifetime,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The distribution of the lifetime of connections`),
+	)
+	mustNoError(err)
+
+	connUsesHistogram, err := meter.Int64Histogram(dbClientConnectionUses,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The distribution of the number of queries executed per connection`),
+	)
+	mustNoError(err)
+
+	connWaitTimeHistogram, err := meter.Float64Histogram(dbClientConnectionWaitTime,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The time it took to obtain an open connection from the pool`),
+	)
+	mustNoError(err)
+
+	connCreateTimeHistogram, err := meter.Float64Histogram(dbClientConnectionCreateTime,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The time it took to create a new connection`),
+	)
+	mustNoError(err)
+
+	connDiscardsCounter, err := meter.Int64Counter(dbClientConnectionDiscards,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The number of connections discarded by the pool`),
+	)
+	mustNoError(err)
+
+	returnedRowsHistogram, err := meter.Int64Histogram(dbClientResponseReturnedRows,
+		metric.WithUnit(unitRow),
+		metric.WithDescription(`The distribution of the number of rows returned by queries`),
+	)
+	mustNoError(err)
+
+	returnedBytesHistogram, err := meter.Int64Histogram(dbClientResponseReturnedBytes,
+		metric.WithUnit(unitBytes),
+		metric.WithDescription(`The distribution of the approximate size of the values returned by queries`),
+	)
+	mustNoError(err)
+
+	rowsAffectedHistogram, err := meter.Int64Histogram(dbClientRowsAffected,
+		metric.WithUnit(unitRow),
+		metric.WithDescription(`The distribution of the number of rows affected by execs`),
+	)
+	mustNoError(err)
+
+	timeToFirstRowHistogram, err := meter.Float64Histogram(dbClientResponseTimeToFirstRow,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The distribution of the time since queries started until they returned the first row`),
+	)
+	mustNoError(err)
+
+	responseDurationHistogram, err := meter.Float64Histogram(dbClientResponseDuration,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The distribution of the time since queries started until their rows were consumed or closed`),
+	)
+	mustNoError(err)
+
+	stmtPreparesCounter, err := meter.Int64Counter(dbClientStatementPrepares,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The number of statements prepared, by query fingerprint with RecordPrepareFingerprint()`),
+	)
+	mustNoError(err)
+
+	stmtOpenCounter, err := meter.Int64UpDownCounter(dbClientStatementsOpen,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The number of prepared statements that are not closed yet`),
+	)
+	mustNoError(err)
+
+	stmtLifetimeHistogram, err := meter.Float64Histogram(dbClientStatementLifetime,
+		metric.WithUnit(unitSeconds),
+		metric.WithDescription(`The distribution of the lifetime of prepared statements`),
+	)
+	mustNoError(err)
+
+	stmtExecutionsHistogram, err := meter.Int64Histogram(dbClientStatementExecutions,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The distribution of the number of executions per prepared statement`),
+	)
+	mustNoError(err)
+
+	nPlusOneCounter, err := meter.Int64Counter(dbClientNPlusOne,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription(`The number of queries executed more times than the threshold within a parent span`),
+	)
+	mustNoError(err)
+
+	var nPlusOne *nPlusOneDetector
+
+	if opts.detectNPlusOne {
+		nPlusOne = newNPlusOneDetector(opts.nPlusOne, nPlusOneCounter.Add, opts.defaultAttributes...)
+	}
+
+	latencyRecorder := newMethodRecorder(latencyMsHistogram.Record, callsCounter.Add, opts.errorPolicies, opts.defaultAttributes...)
+	rowsRecorder := newRowsRecorder(
+		returnedRowsHistogram.Record, returnedBytesHistogram.Record, rowsAffectedHistogram.Record,
+		timeToFirstRowHistogram.Record, responseDurationHistogram.Record,
+		opts.defaultAttributes...,
+	)
+	connRecorder := newConnRecorder(
+		connLifetimeHistogram.Record, connUsesHistogram.Record, connWaitTimeHistogram.Record,
+		connCreateTimeHistogram.Record, connDiscardsCounter.Add,
+		opts.defaultAttributes...,
+	)
+	stmtRecorder := newStmtRecorder(
+		stmtPreparesCounter.Add, stmtOpenCounter.Add, stmtLifetimeHistogram.Record, stmtExecutionsHistogram.Record,
+		opts.prepareFingerprint, opts.defaultAttributes...,
+	)
 
 	return connConfig{
 		pingFuncMiddlewares:         makePingFuncMiddlewares(latencyRecorder, tracerOrNil(tracer, opts.trace.Ping)),
-		execContextFuncMiddlewares:  makeExecContextFuncMiddlewares(latencyRecorder, tracer, newExecConfig(opts, metricMethodExec, traceMethodExec)),
-		queryContextFuncMiddlewares: makeQueryerContextMiddlewares(latencyRecorder, tracer, newQueryConfig(opts, metricMethodQuery, traceMethodQuery)),
+		execContextFuncMiddlewares:  makeExecContextFuncMiddlewares(latencyRecorder, tracer, newExecConfig(opts, rowsRecorder, nPlusOne, metricMethodExec, traceMethodExec)),
+		queryContextFuncMiddlewares: makeQueryerContextMiddlewares(latencyRecorder, tracer, newQueryConfig(opts, rowsRecorder, nPlusOne, metricMethodQuery, traceMethodQuery)),
 		beginFuncMiddlewares:        makeBeginFuncMiddlewares(latencyRecorder, tracer),
 		prepareFuncMiddlewares: makePrepareContextFuncMiddlewares(latencyRecorder, tracer, prepareConfig{
 			traceQuery:                  opts.trace.queryTracer,
-			execFuncMiddlewares:         makeExecContextFuncMiddlewares(latencyRecorder, tracerOrNil(tracer, opts.trace.AllowRoot), newExecConfig(opts, metricMethodStmtExec, traceMethodStmtExec)),
-			execContextFuncMiddlewares:  makeExecContextFuncMiddlewares(latencyRecorder, tracer, newExecConfig(opts, metricMethodStmtExec, traceMethodStmtExec)),
-			queryFuncMiddlewares:        makeQueryerContextMiddlewares(latencyRecorder, tracerOrNil(tracer, opts.trace.AllowRoot), newQueryConfig(opts, metricMethodStmtQuery, traceMethodStmtQuery)),
-			queryContextFuncMiddlewares: makeQueryerContextMiddlewares(latencyRecorder, tracer, newQueryConfig(opts, metricMethodStmtQuery, traceMethodStmtQuery)),
+			stmtRecorder:                stmtRecorder,
+			execFuncMiddlewares:         makeExecContextFuncMiddlewares(latencyRecorder, tracerOrNil(tracer, opts.trace.AllowRoot), newExecConfig(opts, rowsRecorder, nPlusOne, metricMethodStmtExec, traceMethodStmtExec)),
+			execContextFuncMiddlewares:  makeExecContextFuncMiddlewares(latencyRecorder, tracer, newExecConfig(opts, rowsRecorder, nPlusOne, metricMethodStmtExec, traceMethodStmtExec)),
+			queryFuncMiddlewares:        makeQueryerContextMiddlewares(latencyRecorder, tracerOrNil(tracer, opts.trace.AllowRoot), newQueryConfig(opts, rowsRecorder, nPlusOne, metricMethodStmtQuery, traceMethodStmtQuery)),
+			queryContextFuncMiddlewares: makeQueryerContextMiddlewares(latencyRecorder, tracer, newQueryConfig(opts, rowsRecorder, nPlusOne, metricMethodStmtQuery, traceMethodStmtQuery)),
+			closeFuncMiddlewares:        makeStmtCloseFuncMiddlewares(latencyRecorder, stmtRecorder, tracerOrNil(tracer, opts.trace.StmtClose)),
 		}),
+		closeFuncMiddlewares:        makeConnCloseFuncMiddlewares(latencyRecorder, connRecorder, tracerOrNil(tracer, opts.trace.ConnClose)),
+		resetSessionFuncMiddlewares: makeResetSessionFuncMiddlewares(latencyRecorder, connRecorder, tracerOrNil(tracer, opts.trace.ResetSession)),
+		isValidFuncMiddlewares:      makeIsValidFuncMiddlewares(connRecorder),
+		connRecorder:                connRecorder,
 	}
 }
 
-var _ driver.Driver = (*otDriver)(nil)
+var (
+	_ driver.Driver        = (*otDriver)(nil)
+	_ driver.DriverContext = (*otDriver)(nil)
+	_ io.Closer            = (*otDriver)(nil)
+	_ driver.Connector     = (*otConnector)(nil)
+)
 
 type otDriver struct {
-	parent    driver.Driver
-	connector driver.Connector
-	close     func() error
+	parent driver.Driver
 
 	connConfig connConfig
 }
 
 func (d otDriver) Open(name string) (driver.Conn, error) {
-	c, err := d.parent.Open(name)
+	return connect(context.Background(), func(context.Context) (driver.Conn, error) {
+		return d.parent.Open(name)
+	}, d.connConfig)
+}
+
+func (d otDriver) OpenConnector(name string) (driver.Connector, error) {
+	c, err := d.parent.(driver.DriverContext).OpenConnector(name) //nolint: errcheck
 	if err != nil {
 		return nil, err
 	}
 
-	return wrapConn(c, d.connConfig), nil
+	return otConnector{
+		parent:     c,
+		driver:     d,
+		connConfig: d.connConfig,
+	}, nil
 }
 
+// Close closes the underlying driver.Driver if it is an io.Closer.
 func (d otDriver) Close() error {
-	return d.close()
+	if closer, ok := d.parent.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
 }
 
-func (d otDriver) OpenConnector(name string) (driver.Connector, error) {
-	var err error
+// Unwrap returns the underlying driver.Driver.
+func (d otDriver) Unwrap() driver.Driver {
+	return d.parent
+}
 
-	d.connector, err = d.parent.(driver.DriverContext).OpenConnector(name) //nolint: errcheck
-	if err != nil {
-		return nil, err
-	}
+type otConnector struct {
+	parent driver.Connector
+	driver driver.Driver
 
-	if c, ok := d.connector.(io.Closer); ok {
-		d.close = c.Close
-	}
+	connConfig connConfig
+}
 
-	return d, err
+func (c otConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return connect(ctx, c.parent.Connect, c.connConfig)
 }
 
-func (d otDriver) Connect(ctx context.Context) (driver.Conn, error) {
-	c, err := d.connector.Connect(ctx)
-	if err != nil {
-		return nil, err
+func (c otConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c otConnector) Close() error {
+	if closer, ok := c.parent.(io.Closer); ok {
+		return closer.Close()
 	}
 
-	return wrapConn(c, d.connConfig), nil
+	return nil
+}
+
+// Unwrap returns the underlying driver.Connector.
+func (c otConnector) Unwrap() driver.Connector {
+	return c.parent
+}
+
+// statsConnector is the connector of the databases opened by OpenDB with WithRecordStats, it stops recording the
+// statistics of the database when the database is closed.
+type statsConnector struct {
+	otConnector
+
+	registration metric.Registration
 }
 
-func (d otDriver) Driver() driver.Driver {
-	return d
+func (c *statsConnector) Close() error {
+	err := c.otConnector.Close()
+
+	if c.registration != nil {
+		err = errors.Join(err, c.registration.Unregister())
+	}
+
+	return err
 }
@@ -9,10 +9,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.opentelemetry.io/otel/trace"
 
@@ -43,13 +48,13 @@ func TestRegister_MaxSlots(t *testing.T) {
 	}{})
 
 	for i := 0; i < numSlots; i++ {
-		driverName, err := otelsql.Register("max-slots")
+		driverName, err := otelsql.Register("max-slots", otelsql.WithInstanceName(strconv.Itoa(i)))
 
 		assert.NotEmpty(t, driverName)
 		assert.NoError(t, err)
 	}
 
-	driverName, err := otelsql.Register("max-slots")
+	driverName, err := otelsql.Register("max-slots", otelsql.WithInstanceName(strconv.Itoa(numSlots)))
 
 	expected := errors.New("unable to register driver, all slots have been taken")
 
@@ -57,6 +62,108 @@ func TestRegister_MaxSlots(t *testing.T) {
 	assert.Equal(t, expected, err)
 }
 
+func TestRegister_IdenticalOptions(t *testing.T) {
+	t.Parallel()
+
+	sql.Register("identical-options", struct {
+		driver.Driver
+	}{})
+
+	first, err := otelsql.Register("identical-options", otelsql.TracePing(), otelsql.WithInstanceName("test"))
+	require.NoError(t, err)
+
+	second, err := otelsql.Register("identical-options", otelsql.TracePing(), otelsql.WithInstanceName("test"))
+	require.NoError(t, err)
+
+	other, err := otelsql.Register("identical-options", otelsql.TracePing(), otelsql.WithInstanceName("other"))
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestRegister_Closure(t *testing.T) {
+	t.Parallel()
+
+	sql.Register("closure", struct {
+		driver.Driver
+	}{})
+
+	formatter := otelsql.WithSpanNameFormatter(func(_ context.Context, op string) string {
+		return op
+	})
+
+	first, err := otelsql.Register("closure", formatter)
+	require.NoError(t, err)
+
+	second, err := otelsql.Register("closure", formatter)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestRegister_WithDriverName(t *testing.T) {
+	t.Parallel()
+
+	sql.Register("with-driver-name", struct {
+		driver.Driver
+	}{})
+
+	driverName, err := otelsql.Register("with-driver-name", otelsql.WithDriverName("with-driver-name-traced"), otelsql.TracePing())
+	require.NoError(t, err)
+
+	assert.Equal(t, "with-driver-name-traced", driverName)
+
+	driverName, err = otelsql.Register("with-driver-name", otelsql.WithDriverName("with-driver-name-traced"), otelsql.TracePing())
+	require.NoError(t, err)
+
+	assert.Equal(t, "with-driver-name-traced", driverName)
+
+	driverName, err = otelsql.Register("with-driver-name", otelsql.WithDriverName("with-driver-name-traced"))
+	expected := errors.New(`otelsql: driver "with-driver-name-traced" is already registered with different options`)
+
+	assert.Empty(t, driverName)
+	assert.Equal(t, expected, err)
+
+	driverName, err = otelsql.Register("with-driver-name", otelsql.WithDriverName("with-driver-name"))
+	expected = errors.New(`otelsql: driver "with-driver-name" is already registered`)
+
+	assert.Empty(t, driverName)
+	assert.Equal(t, expected, err)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	parent := struct {
+		driver.Driver
+	}{}
+
+	sql.Register("lookup", parent)
+
+	driverName := otelsql.MustRegister("lookup", otelsql.TracePing())
+
+	actual, ok := otelsql.Lookup(driverName)
+	require.True(t, ok)
+
+	assert.Equal(t, driverName, actual.Name)
+	assert.Equal(t, "lookup", actual.ParentName)
+	assert.Equal(t, parent, actual.Parent)
+	assert.Len(t, actual.Options(), 1)
+
+	_, ok = otelsql.Lookup("lookup")
+
+	assert.False(t, ok)
+}
+
+func TestMustRegister_Panic(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		otelsql.MustRegister("must-register-unknown")
+	})
+}
+
 func TestRegister_OpenError(t *testing.T) {
 	t.Parallel()
 
@@ -225,6 +332,33 @@ func TestWrap_DriverContext_CloseBeforeOpenConnector(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestWrap_DriverContext_CloseWithoutCloser(t *testing.T) {
+	t.Parallel()
+
+	parent := struct {
+		driver.Driver
+		driver.DriverContext
+	}{
+		DriverContext: driverOpenConnectorFunc(func(string) (driver.Connector, error) {
+			return struct {
+				driverDriverFunc
+				driverConnectFunc
+			}{}, nil
+		}),
+	}
+
+	drv := otelsql.Wrap(parent).(driver.DriverContext) // nolint: errcheck
+	connector, err := drv.OpenConnector("")
+
+	require.NoError(t, err)
+
+	c, ok := connector.(io.Closer)
+	require.True(t, ok, "connector must implement io.Closer")
+
+	err = c.Close()
+	assert.NoError(t, err)
+}
+
 func TestWrap_DriverContext_CloseError(t *testing.T) {
 	t.Parallel()
 
@@ -275,6 +409,70 @@ func Test_Open_Error(t *testing.T) {
 	assert.Equal(t, expectedError, err)
 }
 
+func TestWrap_Close(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		parent        driver.Driver
+		expectedError error
+	}{
+		{
+			scenario: "not a closer",
+			parent: driverOpenFunc(func(string) (driver.Conn, error) {
+				return nil, nil //nolint: nilnil
+			}),
+		},
+		{
+			scenario: "closer",
+			parent: struct {
+				driverOpenFunc
+				driverCloseFunc
+			}{
+				driverCloseFunc: func() error {
+					return errors.New("close error")
+				},
+			},
+			expectedError: errors.New("close error"),
+		},
+		{
+			scenario: "closer with driver context",
+			parent: struct {
+				driverOpenFunc
+				driverOpenConnectorFunc
+				driverCloseFunc
+			}{
+				driverCloseFunc: func() error {
+					return errors.New("close error")
+				},
+			},
+			expectedError: errors.New("close error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			drv := otelsql.Wrap(tc.parent)
+
+			// The driver context is closed by its driver.Driver.
+			if d, ok := drv.(struct {
+				driver.Driver
+				driver.DriverContext
+			}); ok {
+				drv = d.Driver
+			}
+
+			c, ok := drv.(io.Closer)
+			require.True(t, ok)
+
+			assert.Equal(t, tc.expectedError, c.Close())
+		})
+	}
+}
+
 func Test_OpenConnector_Connect(t *testing.T) {
 	t.Parallel()
 
@@ -578,6 +776,89 @@ func Test_ExecContext_QueryInContext(t *testing.T) {
 		})
 }
 
+func Test_OperationInfoInContext(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectBegin()
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+			m.ExpectCommit()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			var (
+				infos       = make(map[string]otelsql.OperationInfo)
+				traceQuery  []string
+				spanStatus  []string
+				missingInfo []string
+			)
+
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.WithSpanNameFormatter(func(ctx context.Context, op string) string {
+					info, ok := otelsql.OperationInfoFromContext(ctx)
+					if !ok {
+						missingInfo = append(missingInfo, op)
+					}
+
+					infos[op] = info
+
+					return op
+				}),
+				otelsql.TraceQuery(func(ctx context.Context, _ string, _ []driver.NamedValue) []attribute.KeyValue {
+					info, _ := otelsql.OperationInfoFromContext(ctx)
+					traceQuery = append(traceQuery, info.Method)
+
+					return nil
+				}),
+				otelsql.ConvertErrorToSpanStatusContext(func(ctx context.Context, _ error) (codes.Code, string) {
+					info, _ := otelsql.OperationInfoFromContext(ctx)
+					spanStatus = append(spanStatus, info.Method)
+
+					return codes.Ok, ""
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			tx, err := db.BeginTx(context.Background(), nil)
+			require.NoError(t, err)
+
+			_, err = tx.ExecContext(context.Background(), query, "US")
+			require.NoError(t, err)
+
+			require.NoError(t, tx.Commit())
+
+			assert.Empty(t, missingInfo)
+			assert.Equal(t, []string{"exec"}, traceQuery)
+			assert.Equal(t, []string{"begin_transaction", "exec", "commit"}, spanStatus)
+
+			begin, exec, commit := infos["begin_transaction"], infos["exec"], infos["commit"]
+
+			assert.False(t, begin.InTransaction)
+			assert.NotZero(t, begin.ConnectionID)
+			assert.Empty(t, begin.Query)
+
+			assert.Equal(t, "exec", exec.Method)
+			assert.Equal(t, query, exec.Query)
+			assert.Equal(t, 1, exec.ArgsCount)
+			assert.True(t, exec.InTransaction)
+			assert.False(t, exec.Prepared)
+			assert.Equal(t, begin.ConnectionID, exec.ConnectionID)
+			assert.False(t, exec.StartTime.Before(begin.StartTime))
+
+			assert.True(t, commit.InTransaction)
+			assert.Equal(t, begin.ConnectionID, commit.ConnectionID)
+		})
+}
+
 func Test_ExecContext_TraceRowsAffected(t *testing.T) {
 	t.Parallel()
 
@@ -664,50 +945,43 @@ func Test_ExecContext_TraceRowsAffected(t *testing.T) {
 	}
 }
 
-func Test_ExecContext_TraceLastInsertID(t *testing.T) {
+func Test_ExecContext_RecordRowsAffected(t *testing.T) {
 	t.Parallel()
 
-	expectedTraceWithoutParent := expectedExecTraceWithLastInsertID(noParentSpanIDs())
-	expectedTraceWithSampleParent := expectedExecTraceWithLastInsertID(sampleParentSpanIDs())
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
 
 	testCases := []struct {
-		scenario      string
-		context       context.Context
-		suiteOptions  []oteltest.SuiteOption
-		driverOptions []otelsql.DriverOption
+		scenario         string
+		driverOption     otelsql.DriverOption
+		callRowsAffected bool
+		expectedMetrics  string
+		expectedTraces   string
 	}{
 		{
-			scenario: "no parent and not allow root",
-			context:  context.Background(),
-			suiteOptions: []oteltest.SuiteOption{
-				oteltest.TracesEmpty(),
-			},
+			scenario:         "eager",
+			driverOption:     otelsql.RecordRowsAffected(),
+			callRowsAffected: true,
+			expectedMetrics:  expectedMetricsFromFile("exec_rows_affected.json"),
+			expectedTraces:   expectedTracesFromFile("exec_with_rows_affected_attribute.json", parentTraceID, parentSpanID),
 		},
 		{
-			scenario: "no parent and allow root",
-			context:  context.Background(),
-			suiteOptions: []oteltest.SuiteOption{
-				oteltest.TracesMatch(assertFirstSpanIsRoot),
-				oteltest.TracesEqualJSON(expectedTraceWithoutParent),
-			},
-			driverOptions: []otelsql.DriverOption{otelsql.AllowRoot()},
+			scenario:        "eager without calling rows affected",
+			driverOption:    otelsql.RecordRowsAffected(),
+			expectedMetrics: expectedMetricsFromFile("exec_rows_affected.json"),
+			expectedTraces:  expectedTracesFromFile("exec_with_rows_affected_attribute.json", parentTraceID, parentSpanID),
 		},
 		{
-			scenario: "with parent and not allow root",
-			context:  contextWithSampleSpan(),
-			suiteOptions: []oteltest.SuiteOption{
-				oteltest.TracesMatch(assertSpansHaveSameRoot),
-				oteltest.TracesEqualJSON(expectedTraceWithSampleParent),
-			},
+			scenario:         "lazy",
+			driverOption:     otelsql.RecordRowsAffectedLazily(),
+			callRowsAffected: true,
+			expectedMetrics:  expectedMetricsFromFile("exec_rows_affected.json"),
+			expectedTraces:   expectedTracesFromFile("exec_with_query.json", parentTraceID, parentSpanID),
 		},
 		{
-			scenario: "with parent and allow root",
-			context:  contextWithSampleSpan(),
-			suiteOptions: []oteltest.SuiteOption{
-				oteltest.TracesMatch(assertSpansHaveSameRoot),
-				oteltest.TracesEqualJSON(expectedTraceWithSampleParent),
-			},
-			driverOptions: []otelsql.DriverOption{otelsql.AllowRoot()},
+			scenario:        "lazy without calling rows affected",
+			driverOption:    otelsql.RecordRowsAffectedLazily(),
+			expectedMetrics: expectedMetricsFromFile("exec_ok.json"),
+			expectedTraces:  expectedTracesFromFile("exec_with_query.json", parentTraceID, parentSpanID),
 		},
 	}
 
@@ -716,98 +990,839 @@ func Test_ExecContext_TraceLastInsertID(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			tc.suiteOptions = append(tc.suiteOptions,
+			oteltest.New(
+				oteltest.MetricsEqualJSON(tc.expectedMetrics),
+				oteltest.TracesEqualJSON(tc.expectedTraces),
 				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
-					m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+					m.ExpectExec(`DELETE FROM data WHERE country = $1`).
 						WithArgs("US").
-						WillReturnResult(sqlmock.NewResult(1, 0))
+						WillReturnResult(sqlmock.NewResult(0, 10))
 				}),
-			)
-
-			oteltest.New(tc.suiteOptions...).
+			).
 				Run(t, func(sc oteltest.SuiteContext) {
-					tc.driverOptions = append(tc.driverOptions,
+					db, err := newDB(sc.DatabaseDSN(),
 						otelsql.WithMeterProvider(sc.MeterProvider()),
 						otelsql.WithTracerProvider(sc.TracerProvider()),
-						otelsql.TraceLastInsertID(),
+						otelsql.TraceQueryWithoutArgs(),
+						tc.driverOption,
 					)
-
-					db, err := newDB(sc.DatabaseDSN(), tc.driverOptions...)
 					require.NoError(t, err)
 
 					defer db.Close() // nolint: errcheck
 
-					result, err := db.ExecContext(tc.context, `INSERT INTO data VALUES ($1)`, "US")
-
+					result, err := db.ExecContext(contextWithSampleSpan(), `DELETE FROM data WHERE country = $1`, "US")
 					require.NoError(t, err)
 
-					id, err := result.LastInsertId()
+					if !tc.callRowsAffected {
+						return
+					}
 
-					require.Equal(t, int64(1), id)
-					require.NoError(t, err)
+					for i := 0; i < 2; i++ {
+						affectedRows, err := result.RowsAffected()
+
+						require.Equal(t, int64(10), affectedRows)
+						require.NoError(t, err)
+					}
 				})
 		})
 	}
 }
 
-func Test_ExecContext_Error(t *testing.T) {
+func Test_ExecContext_RecordRowsAffected_TraceRowsAffected(t *testing.T) {
 	t.Parallel()
 
-	const execErr testError = "exec error"
-
 	oteltest.New(
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			// The rows affected read by RecordRowsAffected are not traced as a call of the caller.
+			if !assert.Len(t, actual, 1) || !assert.Equal(t, "sql:exec", actual[0].Name) {
+				return false
+			}
+
+			rowsAffected, ok := spanAttribute(actual[0], "db.sql.rows_affected")
+
+			return assert.True(t, ok) && assert.Equal(t, float64(10), rowsAffected)
+		}),
 		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
-			m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+			m.ExpectExec(`DELETE FROM data WHERE country = $1`).
 				WithArgs("US").
-				WillReturnError(execErr)
+				WillReturnResult(sqlmock.NewResult(0, 10))
 		}),
-		oteltest.TracesEqualJSON(expectedExecTraceWithError(noParentSpanIDs())),
 	).
 		Run(t, func(sc oteltest.SuiteContext) {
 			db, err := newDB(sc.DatabaseDSN(),
 				otelsql.WithTracerProvider(sc.TracerProvider()),
-				otelsql.AllowRoot(),
+				otelsql.RecordRowsAffected(),
 				otelsql.TraceRowsAffected(),
 			)
 			require.NoError(t, err)
 
 			defer db.Close() // nolint: errcheck
 
-			result, err := db.ExecContext(context.Background(), `INSERT INTO data VALUES ($1)`, "US")
-
-			assert.Nil(t, result)
-			assert.Equal(t, execErr, err)
+			_, err = db.ExecContext(contextWithSampleSpan(), `DELETE FROM data WHERE country = $1`, "US")
+			require.NoError(t, err)
 		})
 }
 
-func Test_QueryContext(t *testing.T) {
+func Test_ExecContext_RecordRowsAffected_TraceRowsAffected_Called(t *testing.T) {
 	t.Parallel()
 
-	expectedMetrics := expectedQueryMetricOK()
-	expectedTraceNoQueryWithoutParent := expectedQueryTraceNoQuery(noParentSpanIDs())
-	expectedTraceNoQueryWithSampleParent := expectedQueryTraceNoQuery(sampleParentSpanIDs())
-	expectedTraceWithQuery := expectedQueryTraceWithQuery(sampleParentSpanIDs())
-	expectedTraceWithQueryArgs := expectedQueryTraceWithQueryArgs(sampleParentSpanIDs())
-
 	testCases := []struct {
-		scenario        string
-		context         context.Context
-		suiteOptions    []oteltest.SuiteOption
-		driverOptions   []otelsql.DriverOption
-		expectedMetrics string
-		expectedTraces  string
+		scenario     string
+		driverOption otelsql.DriverOption
 	}{
 		{
-			scenario: "no parent and not allow root",
-			context:  context.Background(),
-			suiteOptions: []oteltest.SuiteOption{
-				oteltest.MetricsEqualJSON(expectedMetrics),
-				oteltest.TracesEmpty(),
-			},
+			scenario:     "eager",
+			driverOption: otelsql.RecordRowsAffected(),
 		},
 		{
-			scenario: "no parent and allow root",
-			context:  context.Background(),
+			scenario:     "lazy",
+			driverOption: otelsql.RecordRowsAffectedLazily(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			oteltest.New(
+				oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+					// The RowsAffected call of the caller is still traced.
+					return assert.Len(t, actual, 2) &&
+						assert.Equal(t, "sql:exec", actual[0].Name) &&
+						assert.Equal(t, "sql:rows_affected", actual[1].Name)
+				}),
+				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+					m.ExpectExec(`DELETE FROM data WHERE country = $1`).
+						WithArgs("US").
+						WillReturnResult(sqlmock.NewResult(0, 10))
+				}),
+			).
+				Run(t, func(sc oteltest.SuiteContext) {
+					db, err := newDB(sc.DatabaseDSN(),
+						otelsql.WithTracerProvider(sc.TracerProvider()),
+						tc.driverOption,
+						otelsql.TraceRowsAffected(),
+					)
+					require.NoError(t, err)
+
+					defer db.Close() // nolint: errcheck
+
+					result, err := db.ExecContext(contextWithSampleSpan(), `DELETE FROM data WHERE country = $1`, "US")
+					require.NoError(t, err)
+
+					affectedRows, err := result.RowsAffected()
+
+					require.Equal(t, int64(10), affectedRows)
+					require.NoError(t, err)
+				})
+		})
+	}
+}
+
+func Test_ExecContext_TraceResultEvents(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 2) {
+				return false
+			}
+
+			exec, parent := actual[0], actual[1]
+
+			if !assert.Equal(t, "sql:exec", exec.Name) || !assert.Equal(t, "parent", parent.Name) {
+				return false
+			}
+
+			if !assert.Len(t, parent.Events, 2) {
+				return false
+			}
+
+			lastInsertID, rowsAffected := parent.Events[0], parent.Events[1]
+
+			return assert.Equal(t, "last_insert_id", lastInsertID.Name) &&
+				assert.Equal(t, "rows_affected", rowsAffected.Name) &&
+				assertEventAttribute(t, lastInsertID, "db.sql.last_insert_id", float64(42)) &&
+				assertEventAttribute(t, rowsAffected, "db.sql.rows_affected", float64(10))
+		}),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(42, 10))
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceResultEvents(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			ctx, span := sc.TracerProvider().Tracer("test").Start(context.Background(), "parent")
+
+			result, err := db.ExecContext(ctx, `INSERT INTO data VALUES ($1)`, "US")
+			require.NoError(t, err)
+
+			lastInsertID, err := result.LastInsertId()
+
+			require.Equal(t, int64(42), lastInsertID)
+			require.NoError(t, err)
+
+			affectedRows, err := result.RowsAffected()
+
+			require.Equal(t, int64(10), affectedRows)
+			require.NoError(t, err)
+
+			span.End()
+		})
+}
+
+func Test_ExecContext_TraceResultEvents_AllowRoot(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 3) {
+				return false
+			}
+
+			exec, lastInsertID, rowsAffected := actual[0], actual[1], actual[2]
+
+			// The exec span has ended when the result is read, so the calls are traced with spans instead of events.
+			return assert.Equal(t, "sql:exec", exec.Name) &&
+				assert.Empty(t, exec.Events) &&
+				assert.Equal(t, "sql:last_insert_id", lastInsertID.Name) &&
+				assert.Equal(t, "sql:rows_affected", rowsAffected.Name)
+		}),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(42, 10))
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceResultEvents(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			result, err := db.ExecContext(context.Background(), `INSERT INTO data VALUES ($1)`, "US")
+			require.NoError(t, err)
+
+			lastInsertID, err := result.LastInsertId()
+
+			require.Equal(t, int64(42), lastInsertID)
+			require.NoError(t, err)
+
+			affectedRows, err := result.RowsAffected()
+
+			require.Equal(t, int64(10), affectedRows)
+			require.NoError(t, err)
+		})
+}
+
+func Test_QueryContext_DetectNPlusOne(t *testing.T) {
+	t.Parallel()
+
+	const query = `SELECT * FROM orders WHERE user_id = $1`
+
+	var detected []otelsql.NPlusOne
+
+	oteltest.New(
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 5) {
+				return false
+			}
+
+			parent := actual[4]
+
+			if !assert.Equal(t, "parent", parent.Name) || !assert.Len(t, parent.Events, 1) {
+				return false
+			}
+
+			return assert.Equal(t, "db.n_plus_one", parent.Events[0].Name) &&
+				assertEventAttribute(t, parent.Events[0], "db.sql.n_plus_one.count", float64(3)) &&
+				assertEventAttribute(t, parent.Events[0], "db.sql.n_plus_one.threshold", float64(2))
+		}),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			for i := 1; i <= 4; i++ {
+				m.ExpectQuery(query).
+					WithArgs(i).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			}
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.DetectNPlusOne(
+					otelsql.NPlusOneThreshold(2),
+					otelsql.OnNPlusOne(func(_ context.Context, e otelsql.NPlusOne) {
+						detected = append(detected, e)
+					}),
+				),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			ctx, span := sc.TracerProvider().Tracer("test").Start(context.Background(), "parent")
+
+			for i := 1; i <= 4; i++ {
+				rows, err := db.QueryContext(ctx, query, i)
+				require.NoError(t, err)
+				require.NoError(t, rows.Close())
+			}
+
+			span.End()
+
+			require.Len(t, detected, 1)
+			assert.Equal(t, query, detected[0].Query)
+			assert.Equal(t, 3, detected[0].Count)
+			assert.Equal(t, span.SpanContext().SpanID(), detected[0].ParentSpanID)
+		})
+}
+
+func Test_ExecContext_TraceLastInsertID(t *testing.T) {
+	t.Parallel()
+
+	expectedTraceWithoutParent := expectedExecTraceWithLastInsertID(noParentSpanIDs())
+	expectedTraceWithSampleParent := expectedExecTraceWithLastInsertID(sampleParentSpanIDs())
+
+	testCases := []struct {
+		scenario      string
+		context       context.Context
+		suiteOptions  []oteltest.SuiteOption
+		driverOptions []otelsql.DriverOption
+	}{
+		{
+			scenario: "no parent and not allow root",
+			context:  context.Background(),
+			suiteOptions: []oteltest.SuiteOption{
+				oteltest.TracesEmpty(),
+			},
+		},
+		{
+			scenario: "no parent and allow root",
+			context:  context.Background(),
+			suiteOptions: []oteltest.SuiteOption{
+				oteltest.TracesMatch(assertFirstSpanIsRoot),
+				oteltest.TracesEqualJSON(expectedTraceWithoutParent),
+			},
+			driverOptions: []otelsql.DriverOption{otelsql.AllowRoot()},
+		},
+		{
+			scenario: "with parent and not allow root",
+			context:  contextWithSampleSpan(),
+			suiteOptions: []oteltest.SuiteOption{
+				oteltest.TracesMatch(assertSpansHaveSameRoot),
+				oteltest.TracesEqualJSON(expectedTraceWithSampleParent),
+			},
+		},
+		{
+			scenario: "with parent and allow root",
+			context:  contextWithSampleSpan(),
+			suiteOptions: []oteltest.SuiteOption{
+				oteltest.TracesMatch(assertSpansHaveSameRoot),
+				oteltest.TracesEqualJSON(expectedTraceWithSampleParent),
+			},
+			driverOptions: []otelsql.DriverOption{otelsql.AllowRoot()},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			tc.suiteOptions = append(tc.suiteOptions,
+				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+					m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+						WithArgs("US").
+						WillReturnResult(sqlmock.NewResult(1, 0))
+				}),
+			)
+
+			oteltest.New(tc.suiteOptions...).
+				Run(t, func(sc oteltest.SuiteContext) {
+					tc.driverOptions = append(tc.driverOptions,
+						otelsql.WithMeterProvider(sc.MeterProvider()),
+						otelsql.WithTracerProvider(sc.TracerProvider()),
+						otelsql.TraceLastInsertID(),
+					)
+
+					db, err := newDB(sc.DatabaseDSN(), tc.driverOptions...)
+					require.NoError(t, err)
+
+					defer db.Close() // nolint: errcheck
+
+					result, err := db.ExecContext(tc.context, `INSERT INTO data VALUES ($1)`, "US")
+
+					require.NoError(t, err)
+
+					id, err := result.LastInsertId()
+
+					require.Equal(t, int64(1), id)
+					require.NoError(t, err)
+				})
+		})
+	}
+}
+
+func Test_ExecContext_Error(t *testing.T) {
+	t.Parallel()
+
+	const execErr testError = "exec error"
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+				WithArgs("US").
+				WillReturnError(execErr)
+		}),
+		oteltest.TracesEqualJSON(expectedExecTraceWithError(noParentSpanIDs())),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceRowsAffected(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			result, err := db.ExecContext(context.Background(), `INSERT INTO data VALUES ($1)`, "US")
+
+			assert.Nil(t, result)
+			assert.Equal(t, execErr, err)
+		})
+}
+
+func Test_ExecContext_TraceSQLErrors(t *testing.T) {
+	t.Parallel()
+
+	const execErr sqlStateError = "23505"
+
+	testCases := []struct {
+		scenario       string
+		driverOptions  []otelsql.DriverOption
+		expectedStatus string
+	}{
+		{
+			scenario:       "error",
+			expectedStatus: "Error",
+		},
+		{
+			scenario:       "ignored unique violation",
+			driverOptions:  []otelsql.DriverOption{otelsql.IgnoreUniqueViolations()},
+			expectedStatus: "Ok",
+		},
+		{
+			scenario:       "not ignored serialization failure",
+			driverOptions:  []otelsql.DriverOption{otelsql.IgnoreSerializationFailures()},
+			expectedStatus: "Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			oteltest.New(
+				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+					m.ExpectExec(`INSERT INTO data VALUES ($1)`).
+						WithArgs("US").
+						WillReturnError(fmt.Errorf("insert: %w", execErr))
+				}),
+				oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+					if !assert.Len(t, actual, 1) {
+						return false
+					}
+
+					statusCode, _ := spanAttribute(actual[0], "db.response.status_code")
+					errorType, _ := spanAttribute(actual[0], "error.type")
+
+					return assert.Equal(t, tc.expectedStatus, actual[0].Status.Code) &&
+						assert.Equal(t, "23505", statusCode) &&
+						assert.Equal(t, "otelsql_test.sqlStateError", errorType)
+				}),
+			).
+				Run(t, func(sc oteltest.SuiteContext) {
+					opts := []otelsql.DriverOption{
+						otelsql.WithTracerProvider(sc.TracerProvider()),
+						otelsql.AllowRoot(),
+						otelsql.TraceSQLErrors(),
+					}
+
+					db, err := newDB(sc.DatabaseDSN(), append(opts, tc.driverOptions...)...)
+					require.NoError(t, err)
+
+					defer db.Close() // nolint: errcheck
+
+					_, err = db.ExecContext(context.Background(), `INSERT INTO data VALUES ($1)`, "US")
+
+					require.ErrorIs(t, err, execErr)
+				})
+		})
+	}
+}
+
+func Test_ExecContext_TraceCaller(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	testCases := []struct {
+		scenario         string
+		callerOptions    []otelsql.CallerOption
+		expectedFunction string
+	}{
+		{
+			scenario:         "caller",
+			expectedFunction: "go.nhat.io/otelsql_test.Test_ExecContext_TraceCaller",
+		},
+		{
+			scenario:         "skipped packages",
+			callerOptions:    []otelsql.CallerOption{otelsql.SkipCallerPackages("go.nhat.io/otelsql_test")},
+			expectedFunction: "testing.tRunner",
+		},
+		{
+			scenario:      "other methods",
+			callerOptions: []otelsql.CallerOption{otelsql.CallerMethods("query")},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			oteltest.New(
+				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+					m.ExpectExec(query).
+						WithArgs("US").
+						WillReturnResult(sqlmock.NewResult(0, 10))
+				}),
+				oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+					if !assert.Len(t, actual, 1) {
+						return false
+					}
+
+					function, ok := spanAttribute(actual[0], "code.function")
+					if tc.expectedFunction == "" {
+						return assert.False(t, ok)
+					}
+
+					filepath, _ := spanAttribute(actual[0], "code.filepath")
+					lineno, _ := spanAttribute(actual[0], "code.lineno")
+
+					return assert.True(t, strings.HasPrefix(function.(string), tc.expectedFunction), function) && //nolint: forcetypeassert
+						assert.NotEmpty(t, filepath) &&
+						assert.NotZero(t, lineno)
+				}),
+			).
+				Run(t, func(sc oteltest.SuiteContext) {
+					db, err := newDB(sc.DatabaseDSN(),
+						otelsql.WithTracerProvider(sc.TracerProvider()),
+						otelsql.AllowRoot(),
+						otelsql.TraceCaller(tc.callerOptions...),
+					)
+					require.NoError(t, err)
+
+					defer db.Close() // nolint: errcheck
+
+					_, err = db.ExecContext(context.Background(), query, "US")
+
+					require.NoError(t, err)
+				})
+		})
+	}
+}
+
+func Test_ExecContext_TraceCallerStackTrace(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnError(errors.New("exec error"))
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 1) || !assert.Len(t, actual[0].Events, 1) {
+				return false
+			}
+
+			for _, attr := range actual[0].Events[0].Attributes {
+				if attr.Key == "exception.stacktrace" {
+					return assert.Contains(t, attr.Value.Value, "Test_ExecContext_TraceCallerStackTrace")
+				}
+			}
+
+			return assert.Fail(t, "missing exception.stacktrace")
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceCaller(otelsql.CallerStackTrace()),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			_, err = db.ExecContext(context.Background(), query, "US")
+
+			require.Error(t, err)
+		})
+}
+
+func Test_ExecContext_TraceConnection(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+
+			m.ExpectExec(query).
+				WithArgs("CA").
+				WillReturnResult(sqlmock.NewResult(0, 5))
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 2) {
+				return false
+			}
+
+			firstID, _ := spanAttribute(actual[0], "db.client.connection.id")
+			secondID, _ := spanAttribute(actual[1], "db.client.connection.id")
+			firstUseCount, _ := spanAttribute(actual[0], "db.client.connection.use_count")
+			secondUseCount, _ := spanAttribute(actual[1], "db.client.connection.use_count")
+			_, hasAge := spanAttribute(actual[1], "db.client.connection.age")
+
+			return assert.NotNil(t, firstID) &&
+				assert.Equal(t, firstID, secondID, "spans must be recorded on the same connection") &&
+				assert.InDelta(t, 1, firstUseCount, 0) &&
+				assert.InDelta(t, 2, secondUseCount, 0) &&
+				assert.True(t, hasAge, "missing connection age")
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceConnection(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			db.SetMaxOpenConns(1)
+
+			_, err = db.ExecContext(context.Background(), query, "US")
+			require.NoError(t, err)
+
+			_, err = db.ExecContext(context.Background(), query, "CA")
+			require.NoError(t, err)
+		})
+}
+
+func Test_ExecContext_TraceConnection_ErrSkip(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			// database/sql executes the skipped exec again with a prepared statement.
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnError(driver.ErrSkip)
+
+			m.ExpectPrepare(query).
+				ExpectExec().
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+
+			m.ExpectExec(query).
+				WithArgs("CA").
+				WillReturnResult(sqlmock.NewResult(0, 5))
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.NotEmpty(t, actual) {
+				return false
+			}
+
+			// The skipped exec is not a use of the connection.
+			useCount, _ := spanAttribute(actual[len(actual)-1], "db.client.connection.use_count")
+
+			return assert.InDelta(t, 2, useCount, 0)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceConnection(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			db.SetMaxOpenConns(1)
+
+			_, err = db.ExecContext(context.Background(), query, "US")
+			require.NoError(t, err)
+
+			_, err = db.ExecContext(context.Background(), query, "CA")
+			require.NoError(t, err)
+		})
+}
+
+func Test_ExecContext_ErrSkip_Skipped(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			// database/sql executes the skipped exec again with a prepared statement.
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnError(driver.ErrSkip)
+
+			m.ExpectPrepare(query).
+				ExpectExec().
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 3) {
+				return false
+			}
+
+			// The skipped exec is marked, even though its status is OK.
+			skipped, ok := spanAttribute(actual[0], "db.sql.skipped")
+			_, retried := spanAttribute(actual[2], "db.sql.skipped")
+
+			return assert.True(t, ok) &&
+				assert.Equal(t, true, skipped) &&
+				assert.False(t, retried)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.DisableErrSkip(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			_, err = db.ExecContext(context.Background(), query, "US")
+			require.NoError(t, err)
+		})
+}
+
+func Test_ExecContext_TraceConnection_AcquireStart(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectExec(query).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 1) {
+				return false
+			}
+
+			waitTime, ok := spanAttribute(actual[0], "db.client.connection.wait_time")
+			if !assert.True(t, ok, "missing connection wait time") {
+				return false
+			}
+
+			d, err := time.ParseDuration(waitTime.(string)) //nolint: errcheck,forcetypeassert
+
+			return assert.NoError(t, err) &&
+				assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceConnection(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			db.SetMaxOpenConns(1)
+
+			conn, err := db.Conn(context.Background())
+			require.NoError(t, err)
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+
+				_ = conn.Close() // nolint: errcheck
+			}()
+
+			// Waits for the connection to be released.
+			_, err = db.ExecContext(otelsql.ContextWithAcquireStart(context.Background()), query, "US")
+			require.NoError(t, err)
+		})
+}
+
+func Test_QueryContext(t *testing.T) {
+	t.Parallel()
+
+	expectedMetrics := expectedQueryMetricOK()
+	expectedTraceNoQueryWithoutParent := expectedQueryTraceNoQuery(noParentSpanIDs())
+	expectedTraceNoQueryWithSampleParent := expectedQueryTraceNoQuery(sampleParentSpanIDs())
+	expectedTraceWithQuery := expectedQueryTraceWithQuery(sampleParentSpanIDs())
+	expectedTraceWithQueryArgs := expectedQueryTraceWithQueryArgs(sampleParentSpanIDs())
+
+	testCases := []struct {
+		scenario        string
+		context         context.Context
+		suiteOptions    []oteltest.SuiteOption
+		driverOptions   []otelsql.DriverOption
+		expectedMetrics string
+		expectedTraces  string
+	}{
+		{
+			scenario: "no parent and not allow root",
+			context:  context.Background(),
+			suiteOptions: []oteltest.SuiteOption{
+				oteltest.MetricsEqualJSON(expectedMetrics),
+				oteltest.TracesEmpty(),
+			},
+		},
+		{
+			scenario: "no parent and allow root",
+			context:  context.Background(),
 			suiteOptions: []oteltest.SuiteOption{
 				oteltest.MetricsEqualJSON(expectedMetrics),
 				oteltest.TracesEqualJSON(expectedTraceNoQueryWithoutParent),
@@ -855,77 +1870,310 @@ func Test_QueryContext(t *testing.T) {
 		},
 	}
 
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.scenario, func(t *testing.T) {
-			t.Parallel()
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			tc.suiteOptions = append(tc.suiteOptions,
+				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+					m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
+						WithArgs("US").
+						WillReturnRows(
+							sqlmock.NewRows([]string{"country", "name"}).
+								AddRow("US", "John"),
+						)
+				}),
+			)
+
+			oteltest.New(tc.suiteOptions...).
+				Run(t, func(sc oteltest.SuiteContext) {
+					tc.driverOptions = append(tc.driverOptions,
+						otelsql.WithMeterProvider(sc.MeterProvider()),
+						otelsql.WithTracerProvider(sc.TracerProvider()),
+					)
+					db, err := newDB(sc.DatabaseDSN(), tc.driverOptions...)
+					require.NoError(t, err)
+
+					defer db.Close() // nolint: errcheck
+
+					result, err := db.QueryContext(tc.context, `SELECT * FROM data WHERE country = $1`, "US")
+
+					require.NotNil(t, result)
+					require.NoError(t, err)
+
+					defer result.Close() // nolint: errcheck
+				})
+		})
+	}
+}
+
+func Test_QueryContext_QueryInContext(t *testing.T) {
+	t.Parallel()
+
+	const expected = `SELECT * FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(expected).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John"),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
+					actual := otelsql.QueryFromContext(ctx)
+
+					assert.Equal(t, expected, actual)
+
+					return ""
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			rows, err := db.QueryContext(context.Background(), expected, "US")
+			require.NoError(t, err)
+
+			defer rows.Close() // nolint: errcheck
+		})
+}
+
+func Test_QueryContext_CountRows(t *testing.T) {
+	t.Parallel()
+
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("query_count_rows.json")),
+		oteltest.TracesEqualJSON(expectedTracesFromFile("query_with_rows_count.json", parentTraceID, parentSpanID)),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John").
+						AddRow("US", "Alice"),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceQueryWithoutArgs(),
+				otelsql.CountRows(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			rows, err := db.QueryContext(contextWithSampleSpan(), `SELECT * FROM data WHERE country = $1`, "US")
+			require.NoError(t, err)
+
+			for rows.Next() {
+			}
+
+			require.NoError(t, rows.Err())
+			require.NoError(t, rows.Close())
+		})
+}
+
+func Test_QueryContext_RecordResponseTime(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("query_response_time.json")),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John"),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.RecordResponseTime(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			rows, err := db.QueryContext(context.Background(), `SELECT * FROM data WHERE country = $1`, "US")
+			require.NoError(t, err)
 
-			tc.suiteOptions = append(tc.suiteOptions,
-				oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
-					m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
-						WithArgs("US").
-						WillReturnRows(
-							sqlmock.NewRows([]string{"country", "name"}).
-								AddRow("US", "John"),
-						)
-				}),
-			)
+			for rows.Next() {
+			}
 
-			oteltest.New(tc.suiteOptions...).
-				Run(t, func(sc oteltest.SuiteContext) {
-					tc.driverOptions = append(tc.driverOptions,
-						otelsql.WithMeterProvider(sc.MeterProvider()),
-						otelsql.WithTracerProvider(sc.TracerProvider()),
-					)
-					db, err := newDB(sc.DatabaseDSN(), tc.driverOptions...)
-					require.NoError(t, err)
+			require.NoError(t, rows.Err())
+			require.NoError(t, rows.Close())
+		})
+}
 
-					defer db.Close() // nolint: errcheck
+func Test_QueryContext_TraceUntilRowsClose(t *testing.T) {
+	t.Parallel()
 
-					result, err := db.QueryContext(tc.context, `SELECT * FROM data WHERE country = $1`, "US")
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
 
-					require.NotNil(t, result)
-					require.NoError(t, err)
+	oteltest.New(
+		oteltest.TracesEqualJSON(expectedTracesFromFile("query_until_rows_close.json", parentTraceID, parentSpanID)),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John"),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceQueryWithoutArgs(),
+				otelsql.TraceQueryUntilRowsClose(),
+			)
+			require.NoError(t, err)
 
-					defer result.Close() // nolint: errcheck
-				})
+			defer db.Close() // nolint: errcheck
+
+			var country, name string
+
+			err = db.QueryRowContext(contextWithSampleSpan(), `SELECT * FROM data WHERE country = $1`, "US").
+				Scan(&country, &name)
+
+			require.NoError(t, err)
+			assert.Equal(t, "John", name)
 		})
-	}
 }
 
-func Test_QueryContext_QueryInContext(t *testing.T) {
+func Test_QueryContext_TraceUntilRowsClose_Error(t *testing.T) {
 	t.Parallel()
 
-	const expected = `SELECT * FROM data WHERE country = $1`
+	const rowsErr testError = "rows error"
+
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
 
 	oteltest.New(
+		oteltest.TracesEqualJSON(expectedTracesFromFile("query_until_rows_close_with_error.json", parentTraceID, parentSpanID)),
 		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
-			m.ExpectQuery(expected).
+			m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
 				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John").
+						AddRow("US", "Alice").
+						RowError(1, rowsErr),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceQueryWithoutArgs(),
+				otelsql.TraceQueryUntilRowsClose(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			rows, err := db.QueryContext(contextWithSampleSpan(), `SELECT * FROM data WHERE country = $1`, "US")
+			require.NoError(t, err)
+
+			for rows.Next() {
+			}
+
+			require.ErrorIs(t, rows.Err(), rowsErr)
+			require.NoError(t, rows.Close())
+		})
+}
+
+func Test_QueryContext_TraceRowsResultSets(t *testing.T) {
+	t.Parallel()
+
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
+
+	oteltest.New(
+		oteltest.TracesEqualJSON(expectedTracesFromFile("query_with_result_sets.json", parentTraceID, parentSpanID)),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(`CALL get_data()`).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"country", "name"}).
 						AddRow("US", "John"),
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John").
+						AddRow("US", "Alice"),
 				)
 		}),
 	).
 		Run(t, func(sc oteltest.SuiteContext) {
 			db, err := newDB(sc.DatabaseDSN(),
-				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
-					actual := otelsql.QueryFromContext(ctx)
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceRowsResultSets(),
+			)
+			require.NoError(t, err)
 
-					assert.Equal(t, expected, actual)
+			defer db.Close() // nolint: errcheck
 
-					return ""
-				}),
+			rows, err := db.QueryContext(contextWithSampleSpan(), `CALL get_data()`)
+			require.NoError(t, err)
+
+			for rows.Next() {
+			}
+
+			require.True(t, rows.NextResultSet())
+
+			for rows.Next() {
+			}
+
+			require.False(t, rows.NextResultSet())
+			require.NoError(t, rows.Err())
+			require.NoError(t, rows.Close())
+		})
+}
+
+func Test_QueryContext_TraceRowsNextSummary(t *testing.T) {
+	t.Parallel()
+
+	parentTraceID, parentSpanID := sampleParentSpanIDs()
+
+	oteltest.New(
+		oteltest.TracesEqualJSON(expectedTracesFromFile("query_with_rows_next_summary.json", parentTraceID, parentSpanID)),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectQuery(`SELECT * FROM data WHERE country = $1`).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John").
+						AddRow("US", "Alice"),
+				)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceQueryWithoutArgs(),
+				otelsql.TraceRowsNextSummary(),
 			)
 			require.NoError(t, err)
 
 			defer db.Close() // nolint: errcheck
 
-			rows, err := db.QueryContext(context.Background(), expected, "US")
+			rows, err := db.QueryContext(contextWithSampleSpan(), `SELECT * FROM data WHERE country = $1`, "US")
 			require.NoError(t, err)
 
-			defer rows.Close() // nolint: errcheck
+			for rows.Next() {
+			}
+
+			require.NoError(t, rows.Err())
+			require.NoError(t, rows.Close())
 		})
 }
 
@@ -1760,105 +3008,264 @@ func Test_BeginTx_Rollback_Error(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			defer db.Close() // nolint: errcheck
+			defer db.Close() // nolint: errcheck
+
+			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+
+			require.NotNil(t, tx)
+			require.NoError(t, err)
+
+			err = tx.Rollback()
+
+			require.Equal(t, rollbackErr, err)
+		})
+}
+
+func Test_BeginTx_ExecContext_QueryInContext(t *testing.T) {
+	t.Parallel()
+
+	const expected = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectBegin()
+
+			m.ExpectExec(expected).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+
+			m.ExpectCommit()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
+					actual := otelsql.QueryFromContext(ctx)
+
+					assert.Equal(t, expected, actual)
+
+					return ""
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+
+			require.NotNil(t, tx)
+			require.NoError(t, err)
+
+			defer tx.Commit() // nolint: errcheck
+
+			_, err = db.ExecContext(context.Background(), expected, "US")
+			require.NoError(t, err)
+		})
+}
+
+func Test_BeginTx_QueryContext_QueryInContext(t *testing.T) {
+	t.Parallel()
+
+	const expected = `SELECT * FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectBegin()
+
+			m.ExpectQuery(expected).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John"),
+				)
+
+			m.ExpectCommit()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
+					actual := otelsql.QueryFromContext(ctx)
+
+					assert.Equal(t, expected, actual)
+
+					return ""
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+
+			require.NotNil(t, tx)
+			require.NoError(t, err)
+
+			defer tx.Commit() // nolint: errcheck
+
+			rows, err := db.QueryContext(context.Background(), expected, "US")
+			require.NoError(t, err)
+
+			defer rows.Close() // nolint: errcheck
+		})
+}
+
+func Test_QueryBudget(t *testing.T) {
+	t.Parallel()
+
+	const (
+		execQuery   = `DELETE FROM data WHERE country = $1`
+		selectQuery = `SELECT * FROM data WHERE country = $1`
+	)
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectBegin()
+
+			m.ExpectExec(execQuery).
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+
+			m.ExpectQuery(selectQuery).
+				WithArgs("US").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"country", "name"}).
+						AddRow("US", "John").
+						AddRow("US", "Jane"),
+				)
+
+			m.ExpectCommit()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN())
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			var exceeded bool
+
+			ctx := otelsql.WithQueryBudget(context.Background(),
+				otelsql.MaxQueries(1),
+				otelsql.OnQueryBudgetExceeded(func(context.Context, otelsql.QueryStats) {
+					exceeded = true
+				}),
+			)
+
+			tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+			require.NoError(t, err)
+
+			_, err = tx.ExecContext(ctx, execQuery, "US")
+			require.NoError(t, err)
+
+			rows, err := tx.QueryContext(ctx, selectQuery, "US")
+			require.NoError(t, err)
+
+			var count int
 
-			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+			for rows.Next() {
+				count++
+			}
 
-			require.NotNil(t, tx)
-			require.NoError(t, err)
+			require.Equal(t, 2, count)
+			require.NoError(t, rows.Close())
+			require.NoError(t, tx.Commit())
 
-			err = tx.Rollback()
+			stats, ok := otelsql.QueryStatsFromContext(ctx)
 
-			require.Equal(t, rollbackErr, err)
+			require.True(t, ok)
+			assert.Equal(t, 2, stats.Queries)
+			assert.Equal(t, int64(2), stats.Rows)
+			assert.Equal(t, 0, stats.Errors)
+			assert.Positive(t, stats.Duration)
+			assert.Contains(t, []string{execQuery, selectQuery}, stats.SlowestQuery)
+			assert.True(t, stats.Exceeded)
+			assert.True(t, exceeded)
 		})
 }
 
-func Test_BeginTx_ExecContext_QueryInContext(t *testing.T) {
+func Test_QueryBudget_Commit(t *testing.T) {
 	t.Parallel()
 
-	const expected = `DELETE FROM data WHERE country = $1`
+	const (
+		query               = `DELETE FROM data WHERE country = $1`
+		commitErr testError = "commit error"
+	)
 
 	oteltest.New(
 		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
 			m.ExpectBegin()
 
-			m.ExpectExec(expected).
+			m.ExpectExec(query).
 				WithArgs("US").
 				WillReturnResult(sqlmock.NewResult(0, 10))
 
-			m.ExpectCommit()
+			m.ExpectCommit().
+				WillReturnError(commitErr)
 		}),
 	).
 		Run(t, func(sc oteltest.SuiteContext) {
-			db, err := newDB(sc.DatabaseDSN(),
-				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
-					actual := otelsql.QueryFromContext(ctx)
-
-					assert.Equal(t, expected, actual)
-
-					return ""
-				}),
-			)
+			db, err := newDB(sc.DatabaseDSN())
 			require.NoError(t, err)
 
 			defer db.Close() // nolint: errcheck
 
-			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+			ctx := otelsql.WithQueryBudget(context.Background())
 
-			require.NotNil(t, tx)
+			tx, err := db.BeginTx(ctx, &sql.TxOptions{})
 			require.NoError(t, err)
 
-			defer tx.Commit() // nolint: errcheck
-
-			_, err = db.ExecContext(context.Background(), expected, "US")
+			_, err = tx.ExecContext(ctx, query, "US")
 			require.NoError(t, err)
+
+			before, _ := otelsql.QueryStatsFromContext(ctx)
+
+			err = tx.Commit()
+			require.ErrorIs(t, err, commitErr)
+
+			stats, _ := otelsql.QueryStatsFromContext(ctx)
+
+			// The commit is not a query, but its duration and its error are recorded.
+			assert.Equal(t, 1, stats.Queries)
+			assert.Equal(t, 1, stats.Errors)
+			assert.Greater(t, stats.Duration, before.Duration)
 		})
 }
 
-func Test_BeginTx_QueryContext_QueryInContext(t *testing.T) {
+func Test_QueryBudget_FailOnExceeded(t *testing.T) {
 	t.Parallel()
 
-	const expected = `SELECT * FROM data WHERE country = $1`
+	const query = `DELETE FROM data WHERE country = $1`
 
 	oteltest.New(
 		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
-			m.ExpectBegin()
-
-			m.ExpectQuery(expected).
+			m.ExpectExec(query).
 				WithArgs("US").
-				WillReturnRows(
-					sqlmock.NewRows([]string{"country", "name"}).
-						AddRow("US", "John"),
-				)
-
-			m.ExpectCommit()
+				WillReturnResult(sqlmock.NewResult(0, 10))
 		}),
 	).
 		Run(t, func(sc oteltest.SuiteContext) {
-			db, err := newDB(sc.DatabaseDSN(),
-				otelsql.WithSpanNameFormatter(func(ctx context.Context, _ string) string {
-					actual := otelsql.QueryFromContext(ctx)
-
-					assert.Equal(t, expected, actual)
-
-					return ""
-				}),
-			)
+			db, err := newDB(sc.DatabaseDSN())
 			require.NoError(t, err)
 
 			defer db.Close() // nolint: errcheck
 
-			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{})
+			ctx := otelsql.WithQueryBudget(context.Background(),
+				otelsql.MaxQueries(1),
+				otelsql.FailOnQueryBudgetExceeded(),
+			)
 
-			require.NotNil(t, tx)
+			_, err = db.ExecContext(ctx, query, "US")
 			require.NoError(t, err)
 
-			defer tx.Commit() // nolint: errcheck
+			_, err = db.ExecContext(ctx, query, "CA")
+			require.ErrorIs(t, err, otelsql.ErrQueryBudgetExceeded)
 
-			rows, err := db.QueryContext(context.Background(), expected, "US")
-			require.NoError(t, err)
+			stats, _ := otelsql.QueryStatsFromContext(ctx)
 
-			defer rows.Close() // nolint: errcheck
+			assert.Equal(t, 1, stats.Queries)
+			assert.Equal(t, 1, stats.Errors)
+			assert.True(t, stats.Exceeded)
 		})
 }
 
@@ -2012,6 +3419,75 @@ func Test_PrepareContext_ExecContext(t *testing.T) {
 	}
 }
 
+func Test_PrepareContext_TraceStatement(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			if !assert.Len(t, actual, 3) {
+				return false
+			}
+
+			prepare := actual[0]
+
+			if !assert.Equal(t, "sql:prepare", prepare.Name) {
+				return false
+			}
+
+			for i, exec := range actual[1:] {
+				if !assert.Equal(t, "sql:exec", exec.Name) ||
+					!assert.Equal(t, []oteltest.SpanLink{{SpanContext: prepare.SpanContext}}, exec.Links) {
+					return false
+				}
+
+				useCount, _ := spanAttribute(exec, "db.sql.stmt.use_count")
+
+				if !assert.Equal(t, float64(i+1), useCount) {
+					return false
+				}
+
+				if _, ok := spanAttribute(exec, "db.sql.stmt.age"); !assert.True(t, ok, "missing statement age") {
+					return false
+				}
+			}
+
+			return true
+		}),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			stmt := m.ExpectPrepare(`DELETE FROM data WHERE country = $1`)
+
+			stmt.ExpectExec().
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+
+			stmt.ExpectExec().
+				WithArgs("CA").
+				WillReturnResult(sqlmock.NewResult(0, 5))
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TraceStatement(),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			stmt, err := db.PrepareContext(contextWithSampleSpan(), `DELETE FROM data WHERE country = $1`)
+			require.NoError(t, err)
+
+			defer stmt.Close() // nolint: errcheck
+
+			_, err = stmt.ExecContext(contextWithSampleSpan(), "US")
+			require.NoError(t, err)
+
+			// The second execution belongs to another trace.
+			_, err = stmt.ExecContext(oteltest.BackgroundWithSpanContext(trace.TraceID{1}, trace.SpanID{1}), "CA")
+			require.NoError(t, err)
+		})
+}
+
 func Test_PrepareContext_ExecContext_QueryInContext(t *testing.T) {
 	t.Parallel()
 
@@ -2053,6 +3529,62 @@ func Test_PrepareContext_ExecContext_QueryInContext(t *testing.T) {
 		})
 }
 
+func Test_PrepareContext_QueryInfoInContext(t *testing.T) {
+	t.Parallel()
+
+	const query = `DELETE FROM data WHERE country = $1`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			stmt := m.ExpectPrepare(query).
+				WillBeClosed()
+
+			stmt.ExpectExec().
+				WithArgs("US").
+				WillReturnResult(sqlmock.NewResult(0, 10))
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			infos := make(map[string]otelsql.QueryInfo)
+
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceStmtClose(),
+				otelsql.WithSpanNameFormatter(func(ctx context.Context, op string) string {
+					infos[op], _ = otelsql.QueryInfoFromContext(ctx)
+
+					return op
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			stmt, err := db.PrepareContext(context.Background(), query)
+			require.NoError(t, err)
+
+			_, err = stmt.Exec("US")
+			require.NoError(t, err)
+
+			require.NoError(t, stmt.Close())
+
+			prepare := infos["prepare"]
+
+			assert.Equal(t, query, prepare.Query)
+			assert.NotZero(t, prepare.StatementID)
+			assert.False(t, prepare.PrepareSpan.IsValid())
+
+			for _, op := range []string{"exec", "close_statement"} {
+				actual := infos[op]
+
+				assert.Equal(t, query, actual.Query, op)
+				assert.Equal(t, prepare.StatementID, actual.StatementID, op)
+				assert.True(t, actual.PrepareSpan.IsValid(), op)
+			}
+		})
+}
+
 func Test_PrepareContext_ExecContext_Error(t *testing.T) {
 	t.Parallel()
 
@@ -2726,6 +4258,45 @@ func Test_PrepareContext_QueryContext_TraceRows(t *testing.T) {
 	}
 }
 
+func Test_Close_Trace(t *testing.T) {
+	t.Parallel()
+
+	const query = `SELECT * FROM data`
+
+	oteltest.New(
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectPrepare(query).WillBeClosed()
+		}),
+		oteltest.TracesMatch(func(t assert.TestingT, actual []oteltest.Span) bool {
+			names := make([]string, 0, len(actual))
+
+			for _, span := range actual {
+				names = append(names, span.Name)
+			}
+
+			return assert.Equal(t, []string{"sql:prepare", "sql:close_statement", "sql:close_connection"}, names)
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN(),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.AllowRoot(),
+				otelsql.TraceConnClose(),
+				otelsql.TraceStmtClose(),
+			)
+			require.NoError(t, err)
+
+			stmt, err := db.PrepareContext(context.Background(), query)
+			require.NoError(t, err)
+
+			err = stmt.Close()
+			require.NoError(t, err)
+
+			err = db.Close()
+			require.NoError(t, err)
+		})
+}
+
 func Test_Custom_Setup(t *testing.T) {
 	t.Parallel()
 
@@ -2762,6 +4333,99 @@ func Test_Custom_Setup(t *testing.T) {
 		})
 }
 
+func Test_OpenDB(t *testing.T) {
+	t.Parallel()
+
+	ctx := contextWithSampleSpan()
+
+	expectedMetrics := expectedCustomMetricOK()
+	expectedTraces := expectedCustomTrace(sampleParentSpanIDs())
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetrics),
+		oteltest.TracesEqualJSON(expectedTraces),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectPing()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := otelsql.OpenDB(newSqlmockConnector(t, sc.DatabaseDSN()),
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithTracerProvider(sc.TracerProvider()),
+				otelsql.TracePing(),
+				otelsql.WithDatabaseName("test"),
+				otelsql.WithInstanceName("default"),
+				otelsql.WithSystem(semconv.DBSystemPostgreSQL),
+				otelsql.WithSpanNameFormatter(func(_ context.Context, op string) string {
+					return fmt.Sprintf("custom:sql:%s", op)
+				}),
+			)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			err = db.PingContext(ctx)
+			require.NoError(t, err)
+		})
+}
+
+func Test_OpenDB_RecordStats(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("open_db_stats.json")),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectPing()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := otelsql.OpenDB(newSqlmockConnector(t, sc.DatabaseDSN()),
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithInstanceName("default"),
+				otelsql.WithSystem(semconv.DBSystemPostgreSQL),
+				otelsql.WithRecordStats(otelsql.WithMinimumReadDBStatsInterval(100*time.Millisecond)),
+			)
+			require.NoError(t, err)
+
+			err = db.Ping()
+			require.NoError(t, err)
+		})
+}
+
+func Test_OpenDB_RecordStats_Close(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		// The statistics of a closed database are not recorded.
+		oteltest.MetricsEqualJSON(`[]`),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := otelsql.OpenDB(newSqlmockConnector(t, sc.DatabaseDSN()),
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithRecordStats(),
+			)
+			require.NoError(t, err)
+
+			err = db.Close()
+			require.NoError(t, err)
+		})
+}
+
+func TestWrapConnector(t *testing.T) {
+	t.Parallel()
+
+	parent := newSqlmockConnector(t, "")
+	connector := otelsql.WrapConnector(parent)
+
+	assert.NotNil(t, connector.Driver())
+	assert.NotEqual(t, parent.Driver(), connector.Driver())
+
+	actual, ok := otelsql.Unwrap[sqlmockConnector](connector)
+
+	assert.True(t, ok)
+	assert.Equal(t, parent, actual)
+}
+
 type driverOpenFunc func(name string) (driver.Conn, error)
 
 func (f driverOpenFunc) Open(name string) (driver.Conn, error) {
@@ -2804,6 +4468,16 @@ func (e testError) Error() string {
 	return string(e)
 }
 
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sql state " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
 type dataRow struct {
 	Country string
 	Name    string
@@ -2823,6 +4497,31 @@ func newDB(dsn string, opts ...otelsql.DriverOption) (*sql.DB, error) {
 	return db, nil
 }
 
+// sqlmockConnector opens the sqlmock connections without going through sql.Open.
+type sqlmockConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c sqlmockConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c sqlmockConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func newSqlmockConnector(t *testing.T, dsn string) sqlmockConnector {
+	t.Helper()
+
+	db, err := sql.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	return sqlmockConnector{dsn: dsn, driver: db.Driver()}
+}
+
 func mustNotFail(err error) {
 	if err != nil {
 		panic(err)
@@ -2841,6 +4540,26 @@ func contextWithSampleSpan() context.Context {
 	return oteltest.BackgroundWithSpanContext(sampleParentSpanIDs())
 }
 
+func spanAttribute(span oteltest.Span, key string) (any, bool) {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func assertEventAttribute(t assert.TestingT, event oteltest.SpanEvent, key string, expected any) bool {
+	for _, attr := range event.Attributes {
+		if attr.Key == key {
+			return assert.Equal(t, expected, attr.Value.Value)
+		}
+	}
+
+	return assert.Fail(t, "attribute not found", "event %q has no attribute %q", event.Name, key)
+}
+
 func assertSpanIsRoot(t assert.TestingT, span oteltest.Span, msgAndArgs ...any) bool {
 	return assert.Equal(t, span.Parent.TraceID, oteltest.NilTraceID.String(), msgAndArgs...) &&
 		assert.Equal(t, span.Parent.SpanID, oteltest.NilSpanID.String(), msgAndArgs...)
@@ -0,0 +1,73 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+// ErrorStatus is the status of a failed call, decided by the error policies.
+type ErrorStatus int
+
+const (
+	// ErrorStatusError is the status of the failed calls, and of the errors that no policy recognizes.
+	ErrorStatusError ErrorStatus = iota
+	// ErrorStatusOK is the status of the errors that are not considered as failures.
+	ErrorStatusOK
+	// ErrorStatusCanceled is the status of the calls canceled by the client.
+	ErrorStatusCanceled
+)
+
+// ErrorPolicy decides the status of a call that failed with the error. It returns false if it does not recognize the
+// error, so that the next policy decides.
+type ErrorPolicy func(err error) (ErrorStatus, bool)
+
+// errorPolicies applies the policies in order until one recognizes the error.
+type errorPolicies []ErrorPolicy
+
+func (p errorPolicies) status(err error) ErrorStatus {
+	if err == nil {
+		return ErrorStatusOK
+	}
+
+	for _, policy := range p {
+		if status, ok := policy(err); ok {
+			return status
+		}
+	}
+
+	return ErrorStatusError
+}
+
+// IgnoreContextCanceled is an ErrorPolicy that considers the calls failed with context.Canceled as canceled by the
+// client. The calls failed with context.DeadlineExceeded are still errors.
+func IgnoreContextCanceled(err error) (ErrorStatus, bool) {
+	if errors.Is(err, context.Canceled) {
+		return ErrorStatusCanceled, true
+	}
+
+	return ErrorStatusError, false
+}
+
+// IgnoreNoRows is an ErrorPolicy that considers sql.ErrNoRows as OK.
+//
+// database/sql returns sql.ErrNoRows from Row.Scan, without calling the driver, so this policy only matters for the
+// drivers or the wrapped drivers that return it.
+func IgnoreNoRows(err error) (ErrorStatus, bool) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorStatusOK, true
+	}
+
+	return ErrorStatusError, false
+}
+
+// IgnoreErrSkip is an ErrorPolicy that considers driver.ErrSkip as OK. Unlike DisableErrSkip(), it also applies to the
+// metrics.
+func IgnoreErrSkip(err error) (ErrorStatus, bool) {
+	if errors.Is(err, driver.ErrSkip) {
+		return ErrorStatusOK, true
+	}
+
+	return ErrorStatusError, false
+}
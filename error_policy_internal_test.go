@@ -0,0 +1,84 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPolicies_Status(t *testing.T) {
+	t.Parallel()
+
+	all := errorPolicies{IgnoreContextCanceled, IgnoreNoRows, IgnoreErrSkip}
+
+	testCases := []struct {
+		scenario string
+		policies errorPolicies
+		error    error
+		expected ErrorStatus
+	}{
+		{
+			scenario: "no error",
+			error:    nil,
+			expected: ErrorStatusOK,
+		},
+		{
+			scenario: "no policy",
+			error:    context.Canceled,
+			expected: ErrorStatusError,
+		},
+		{
+			scenario: "canceled",
+			policies: all,
+			error:    fmt.Errorf("query: %w", context.Canceled),
+			expected: ErrorStatusCanceled,
+		},
+		{
+			scenario: "deadline exceeded",
+			policies: all,
+			error:    context.DeadlineExceeded,
+			expected: ErrorStatusError,
+		},
+		{
+			scenario: "no rows",
+			policies: all,
+			error:    sql.ErrNoRows,
+			expected: ErrorStatusOK,
+		},
+		{
+			scenario: "skip",
+			policies: all,
+			error:    driver.ErrSkip,
+			expected: ErrorStatusOK,
+		},
+		{
+			scenario: "unknown error",
+			policies: all,
+			error:    errors.New("error"),
+			expected: ErrorStatusError,
+		},
+		{
+			scenario: "first policy wins",
+			policies: errorPolicies{
+				func(error) (ErrorStatus, bool) { return ErrorStatusError, true },
+				IgnoreContextCanceled,
+			},
+			error:    context.Canceled,
+			expected: ErrorStatusError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.policies.status(tc.error))
+		})
+	}
+}
@@ -14,16 +14,16 @@ type execContextFuncMiddleware = middleware[execContextFunc]
 
 type execContextFunc func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)
 
+// ExecContext satisfies driver.ExecerContext.
+func (f execContextFunc) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return f(ctx, query, args)
+}
+
 // nopExecContext executes nothing.
 func nopExecContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
 	return nil, nil //nolint: nilnil
 }
 
-// skippedExecContext always returns driver.ErrSkip.
-func skippedExecContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
-	return nil, driver.ErrSkip
-}
-
 // execStats records metrics for exec.
 func execStats(r methodRecorder, method string) execContextFuncMiddleware {
 	return func(next execContextFunc) execContextFunc {
@@ -43,19 +43,28 @@ func execStats(r methodRecorder, method string) execContextFuncMiddleware {
 func execTrace(t methodTracer, traceQuery queryTracer, method string) execContextFuncMiddleware {
 	return func(next execContextFunc) execContextFunc {
 		return func(ctx context.Context, query string, args []driver.NamedValue) (result driver.Result, err error) {
-			ctx = ContextWithQuery(ctx, query)
+			ctx = contextWithQueryArgs(contextWithQuery(ctx, query), args)
 			ctx, end := t.Trace(ctx, method)
 
 			defer func() {
-				end(err, traceQuery(ctx, query, args)...)
+				attrs := append(traceQuery(ctx, query, args), skippedAttributes(err)...)
+
+				// The rows affected are read eagerly, so they are added to the span.
+				if a := rowsAffectedFromContext(ctx); a != nil && err == nil {
+					attrs = append(attrs, a.attributes()...)
+				}
+
+				end(err, attrs...)
 			}()
 
-			return next(ctx, query, args)
+			result, err = next(ctx, query, args)
+
+			return
 		}
 	}
 }
 
-func execWrapResult(t methodTracer, traceLastInsertID bool, traceRowsAffected bool) execContextFuncMiddleware {
+func execWrapResult(t methodTracer, traceLastInsertID bool, traceRowsAffected bool, events bool) execContextFuncMiddleware {
 	return func(next execContextFunc) execContextFunc {
 		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
 			result, err := next(ctx, query, args)
@@ -65,24 +74,34 @@ func execWrapResult(t methodTracer, traceLastInsertID bool, traceRowsAffected bo
 
 			shouldTrace, _ := t.ShouldTrace(ctx)
 
-			return wrapResult(ctx, result, t, shouldTrace && traceLastInsertID, shouldTrace && traceRowsAffected), nil
+			return wrapResult(ctx, result, t, shouldTrace && traceLastInsertID, shouldTrace && traceRowsAffected, events), nil
 		}
 	}
 }
 
 func makeExecContextFuncMiddlewares(r methodRecorder, t methodTracer, cfg execConfig) []execContextFuncMiddleware {
-	middlewares := make([]middleware[execContextFunc], 0, 3)
+	middlewares := make([]middleware[execContextFunc], 0, 7)
+
+	middlewares = append(middlewares, execStats(r, cfg.metricMethod), execQueryBudget())
 
-	middlewares = append(middlewares, execStats(r, cfg.metricMethod))
+	if cfg.nPlusOne != nil {
+		middlewares = append(middlewares, execDetectNPlusOne(cfg.nPlusOne))
+	}
 
-	if t == nil {
-		return middlewares
+	if cfg.rowsAffected != rowsAffectedNone {
+		middlewares = append(middlewares, execRecordRowsAffected(cfg.rowsRecorder, cfg.metricMethod, cfg.rowsAffected))
 	}
 
-	middlewares = append(middlewares, execTrace(t, cfg.traceQuery, cfg.traceMethod))
+	if t != nil {
+		middlewares = append(middlewares, execTrace(t, cfg.traceQuery, cfg.traceMethod))
+
+		if cfg.traceLastInsertID || cfg.traceRowsAffected {
+			middlewares = append(middlewares, execWrapResult(t, cfg.traceLastInsertID, cfg.traceRowsAffected, cfg.traceResultEvents))
+		}
+	}
 
-	if cfg.traceLastInsertID || cfg.traceRowsAffected {
-		middlewares = append(middlewares, execWrapResult(t, cfg.traceLastInsertID, cfg.traceRowsAffected))
+	if cfg.rowsAffected == rowsAffectedEager {
+		middlewares = append(middlewares, execLoadRowsAffected())
 	}
 
 	return middlewares
@@ -94,14 +113,25 @@ type execConfig struct {
 	traceQuery        queryTracer
 	traceLastInsertID bool
 	traceRowsAffected bool
+	// traceResultEvents adds events instead of creating spans on LastInsertId and RowsAffected calls.
+	traceResultEvents bool
+	// rowsAffected is how the rows affected are recorded by rowsRecorder.
+	rowsAffected rowsAffectedMode
+	rowsRecorder rowsRecorder
+	// nPlusOne detects the N+1 queries, if not nil.
+	nPlusOne *nPlusOneDetector
 }
 
-func newExecConfig(opts driverOptions, metricMethod, traceMethod string) execConfig {
+func newExecConfig(opts driverOptions, rowsRecorder rowsRecorder, nPlusOne *nPlusOneDetector, metricMethod, traceMethod string) execConfig {
 	return execConfig{
 		metricMethod:      metricMethod,
 		traceMethod:       traceMethod,
 		traceQuery:        opts.trace.queryTracer,
-		traceLastInsertID: opts.trace.LastInsertID,
-		traceRowsAffected: opts.trace.RowsAffected,
+		traceLastInsertID: opts.trace.LastInsertID || opts.trace.ResultEvents,
+		traceRowsAffected: opts.trace.RowsAffected || opts.trace.ResultEvents,
+		traceResultEvents: opts.trace.ResultEvents,
+		rowsAffected:      opts.rowsAffected,
+		rowsRecorder:      rowsRecorder,
+		nPlusOne:          nPlusOne,
 	}
 }
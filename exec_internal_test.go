@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 
@@ -23,7 +24,7 @@ func BenchmarkExecStats(b *testing.B) {
 	count, err := meter.Int64Counter("calls")
 	require.NoError(b, err)
 
-	r := newMethodRecorder(histogram.Record, count.Add,
+	r := newMethodRecorder(histogram.Record, count.Add, nil,
 		semconv.DBSystemOtherSQL,
 		dbInstance.String("test"),
 	)
@@ -49,15 +50,6 @@ func TestNopExecContext(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestSkippedExecContext(t *testing.T) {
-	t.Parallel()
-
-	result, err := skippedExecContext(context.Background(), "", nil)
-
-	assert.Nil(t, result)
-	assert.ErrorIs(t, err, driver.ErrSkip)
-}
-
 func TestChainExecContextFuncMiddlewares_NoMiddleware(t *testing.T) {
 	t.Parallel()
 
@@ -115,6 +107,7 @@ func TestExecStats(t *testing.T) {
 	testCases := []struct {
 		scenario string
 		execer   execContextFunc
+		policies errorPolicies
 		expected string
 	}{
 		{
@@ -134,6 +127,24 @@ func TestExecStats(t *testing.T) {
 				}
 			]`,
 		},
+		{
+			scenario: "canceled by policy",
+			execer: func(_ context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+				return nil, context.Canceled
+			},
+			policies: errorPolicies{IgnoreContextCanceled},
+			expected: `[
+				{
+					"Name": "db.sql.client.calls{service.name=otelsql,instrumentation.name=exec_test,db.instance=test,db.operation=go.sql.exec,db.sql.status=CANCELED,db.system=other_sql}",
+					"Sum": 1
+				},
+				{
+					"Name": "db.sql.client.latency{service.name=otelsql,instrumentation.name=exec_test,db.instance=test,db.operation=go.sql.exec,db.sql.status=CANCELED,db.system=other_sql}",
+					"Sum": "<ignore-diff>",
+					"Count": 1
+				}
+			]`,
+		},
 		{
 			scenario: "no error",
 			execer:   nopExecContext,
@@ -166,7 +177,7 @@ func TestExecStats(t *testing.T) {
 					count, err := meter.Int64Counter(dbSQLClientCalls)
 					require.NoError(t, err)
 
-					r := newMethodRecorder(histogram.Record, count.Add,
+					r := newMethodRecorder(histogram.Record, count.Add, tc.policies,
 						semconv.DBSystemOtherSQL,
 						dbInstance.String("test"),
 					)
@@ -180,3 +191,94 @@ func TestExecStats(t *testing.T) {
 		})
 	}
 }
+
+func TestExecRecordRowsAffected_NilResult(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		mode     rowsAffectedMode
+	}{
+		{
+			scenario: "eager",
+			mode:     rowsAffectedEager,
+		},
+		{
+			scenario: "lazy",
+			mode:     rowsAffectedLazy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var recorded []int64
+
+			recordRowsAffected := func(_ context.Context, value int64, _ ...metric.RecordOption) {
+				recorded = append(recorded, value)
+			}
+
+			r := newRowsRecorder(nil, nil, recordRowsAffected, nil, nil)
+
+			exec := chainMiddlewares([]execContextFuncMiddleware{
+				execRecordRowsAffected(r, metricMethodExec, tc.mode),
+				execLoadRowsAffected(),
+			}, nopExecContext)
+
+			result, err := exec(context.Background(), "", nil)
+
+			assert.Nil(t, result)
+			assert.NoError(t, err)
+			assert.Empty(t, recorded)
+		})
+	}
+}
+
+func TestExecRecordRowsAffected_Eager_DriverCalledOnce(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls    int
+		recorded []int64
+	)
+
+	recordRowsAffected := func(_ context.Context, value int64, _ ...metric.RecordOption) {
+		recorded = append(recorded, value)
+	}
+
+	exec := chainMiddlewares([]execContextFuncMiddleware{
+		execRecordRowsAffected(newRowsRecorder(nil, nil, recordRowsAffected, nil, nil), metricMethodExec, rowsAffectedEager),
+		execWrapResult(newMethodTracer(nil), false, true, false),
+		execLoadRowsAffected(),
+	}, func(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+		return resultRowsAffectedFunc(func() (int64, error) {
+			calls++
+
+			return 10, nil
+		}), nil
+	})
+
+	result, err := exec(context.Background(), "", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		n, err := result.RowsAffected()
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), n)
+	}
+
+	assert.Equal(t, 1, calls, "the driver must be called only once")
+	assert.Equal(t, []int64{10}, recorded)
+}
+
+type resultRowsAffectedFunc func() (int64, error)
+
+func (resultRowsAffectedFunc) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (f resultRowsAffectedFunc) RowsAffected() (int64, error) {
+	return f()
+}
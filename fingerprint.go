@@ -0,0 +1,116 @@
+package otelsql
+
+import (
+	"bytes"
+	"hash/fnv"
+	"strconv"
+)
+
+// queryFingerprint identifies the shape of a query. The queries that only differ by their literals, their placeholders,
+// the number of values in a list, the case or the spaces have the same fingerprint.
+func queryFingerprint(query string) string {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(normalizeQuery(query))) //nolint: errcheck
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// normalizeQuery replaces the literals and the placeholders of the query with ?, collapses the lists of values and the
+// spaces, and lowercases the query.
+func normalizeQuery(query string) string {
+	var (
+		b     = make([]byte, 0, len(query))
+		space bool
+	)
+
+	write := func(c byte) {
+		if space && len(b) > 0 {
+			b = append(b, ' ')
+		}
+
+		space = false
+		b = append(b, c)
+	}
+
+	placeholder := func() {
+		// Collapse the lists of values, for example IN (?, ?, ?) becomes IN (?).
+		if bytes.HasSuffix(b, []byte("?,")) {
+			b = b[:len(b)-1]
+			space = false
+
+			return
+		}
+
+		write('?')
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case isSpace(c):
+			space = true
+			i++
+
+		case c == '\'':
+			i = endOfString(query, i)
+
+			placeholder()
+
+		case isDigit(c) && (len(b) == 0 || space || !isIdentifier(b[len(b)-1])):
+			for i++; i < len(query) && (isDigit(query[i]) || query[i] == '.'); i++ {
+			}
+
+			placeholder()
+
+		case c == '?' || c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			for i++; i < len(query) && isDigit(query[i]); i++ {
+			}
+
+			placeholder()
+
+		default:
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+
+			write(c)
+			i++
+		}
+	}
+
+	return string(b)
+}
+
+// endOfString returns the position after the string literal starting at i.
+func endOfString(query string, i int) int {
+	for i++; i < len(query); i++ {
+		if query[i] != '\'' {
+			continue
+		}
+
+		// A quote is escaped by doubling it.
+		if i+1 < len(query) && query[i+1] == '\'' {
+			i++
+
+			continue
+		}
+
+		return i + 1
+	}
+
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_'
+}
@@ -0,0 +1,62 @@
+package otelsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		query    string
+		expected string
+	}{
+		{
+			scenario: "spaces and case",
+			query:    "SELECT *\n\tFROM  users ",
+			expected: "select * from users",
+		},
+		{
+			scenario: "literals",
+			query:    "SELECT * FROM users WHERE name = 'O''Brien' AND age > 42 AND score < 1.5",
+			expected: "select * from users where name = ? and age > ? and score < ?",
+		},
+		{
+			scenario: "placeholders",
+			query:    "SELECT * FROM users WHERE id = $1 OR id = ? OR id = $12",
+			expected: "select * from users where id = ? or id = ? or id = ?",
+		},
+		{
+			scenario: "identifiers with digits",
+			query:    "SELECT t1.col2 FROM table3 t1",
+			expected: "select t1.col2 from table3 t1",
+		},
+		{
+			scenario: "list of values",
+			query:    "SELECT * FROM users WHERE id IN (1, 2, 3) AND name IN ($1,$2)",
+			expected: "select * from users where id in (?) and name in (?)",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, normalizeQuery(tc.query))
+		})
+	}
+}
+
+func TestQueryFingerprint(t *testing.T) {
+	t.Parallel()
+
+	expected := queryFingerprint("SELECT * FROM users WHERE id = 1")
+
+	assert.Equal(t, expected, queryFingerprint("select *  from users where id = $1"))
+	assert.NotEqual(t, expected, queryFingerprint("SELECT * FROM posts WHERE id = 1"))
+}
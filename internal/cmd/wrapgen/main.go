@@ -0,0 +1,163 @@
+// Package main generates the combinations of the optional database/sql/driver interfaces that the wrappers expose.
+//
+// A wrapper must implement an optional interface if, and only if, the parent implements it, otherwise database/sql
+// either loses a feature (e.g. driver.Validator) or takes a wrong code path. Go cannot add methods to a value at
+// runtime, so every combination is spelled out as an anonymous struct.
+//
+// The deprecated driver.Execer and driver.Queryer are not forwarded. Without driver.ExecerContext and
+// driver.QueryerContext, database/sql prepares a statement instead, which is instrumented, so the drivers that only
+// implement the deprecated interfaces keep working.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"text/template"
+)
+
+type optional struct {
+	Type  string
+	Param string
+}
+
+type combination struct {
+	Func   string
+	Base   string
+	Param  string
+	Result string
+	// Hide wraps the base in a struct even when the parent implements none of the optional interfaces, to hide the
+	// optional methods of the base.
+	Hide      bool
+	Optionals []optional
+}
+
+type embedded struct {
+	Type  string
+	Value string
+}
+
+func (c combination) Cases() [][]embedded {
+	cases := make([][]embedded, 0, 1<<len(c.Optionals))
+
+	for mask := 0; mask < 1<<len(c.Optionals); mask++ {
+		fields := make([]embedded, 0, len(c.Optionals))
+
+		for i, o := range c.Optionals {
+			if mask&(1<<i) != 0 {
+				fields = append(fields, embedded{Type: o.Type, Value: o.Param})
+			}
+		}
+
+		cases = append(cases, fields)
+	}
+
+	return cases
+}
+
+var combinations = []combination{
+	{
+		Func:   "combineConn",
+		Base:   "conn",
+		Param:  "c",
+		Result: "driver.Conn",
+		Optionals: []optional{
+			{Type: "driver.Pinger", Param: "p"},
+			{Type: "driver.ExecerContext", Param: "e"},
+			{Type: "driver.QueryerContext", Param: "q"},
+			{Type: "driver.NamedValueChecker", Param: "n"},
+			{Type: "driver.SessionResetter", Param: "s"},
+			{Type: "driver.Validator", Param: "v"},
+		},
+	},
+	{
+		Func:   "combineStmt",
+		Base:   "wrappedStmt",
+		Param:  "s",
+		Result: "driver.Stmt",
+		Hide:   true,
+		Optionals: []optional{
+			{Type: "driver.StmtExecContext", Param: "e"},
+			{Type: "driver.StmtQueryContext", Param: "q"},
+			{Type: "columnConverter", Param: "c"},
+			{Type: "driver.NamedValueChecker", Param: "n"},
+		},
+	},
+	{
+		Func:   "combineRows",
+		Base:   "rows",
+		Param:  "r",
+		Result: "driver.Rows",
+		Optionals: []optional{
+			{Type: "withRowsNextResultSet", Param: "nrs"},
+			{Type: "withRowsColumnTypeDatabaseTypeName", Param: "dtn"},
+			{Type: "withRowsColumnTypeLength", Param: "l"},
+			{Type: "withRowsColumnTypeNullable", Param: "n"},
+			{Type: "withRowsColumnTypePrecisionScale", Param: "ps"},
+			{Type: "withRowsColumnTypeScanType", Param: "st"},
+		},
+	},
+}
+
+var tpl = template.Must(template.New("").Parse(`// Code generated by go run ./internal/cmd/wrapgen; DO NOT EDIT.
+
+package otelsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+{{ range $c := . }}
+// {{ $c.Func }} returns a {{ $c.Result }} that implements exactly the optional interfaces that are not nil.
+//
+// nolint: cyclop,funlen,gocyclo,maintidx
+func {{ $c.Func }}({{ $c.Param }} {{ $c.Base }}{{ range $c.Optionals }}, {{ .Param }} {{ .Type }}{{ end }}) {{ $c.Result }} {
+	var mask uint
+{{ range $i, $o := $c.Optionals }}
+	if {{ $o.Param }} != nil {
+		mask |= 1 << {{ $i }}
+	}
+{{ end }}
+	switch mask {
+{{- range $i, $fields := $c.Cases }}
+	case {{ $i }}:
+{{- if and (eq (len $fields) 0) (not $c.Hide) }}
+		return {{ $c.Param }}
+{{- else }}
+		return struct {
+			{{ $c.Base }}
+{{- range $fields }}
+			{{ .Type }}
+{{- end }}
+		}{ {{- $c.Param }}{{ range $fields }}, {{ .Value }}{{ end -}} }
+{{- end }}
+{{- end }}
+	}
+
+	panic(fmt.Sprintf("otelsql: unexpected {{ $c.Func }} mask %b", mask))
+}
+{{ end }}`))
+
+func main() {
+	out := flag.String("out", "wrap_gen.go", "output file")
+
+	flag.Parse()
+
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, combinations); err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(fmt.Errorf("could not format generated code: %w\n%s", err, buf.String()))
+	}
+
+	if err := os.WriteFile(*out, src, 0o600); err != nil { // nolint: gosec
+		log.Fatal(err)
+	}
+}
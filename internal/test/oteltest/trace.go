@@ -36,7 +36,27 @@ type Span struct {
 	SpanContext SpanContext     `json:"SpanContext"`
 	Parent      SpanContext     `json:"Parent"`
 	SpanKind    int             `json:"SpanKind"`
+	Status      SpanStatus      `json:"Status"`
 	Attributes  []SpanAttribute `json:"Attributes"`
+	Events      []SpanEvent     `json:"Events"`
+	Links       []SpanLink      `json:"Links"`
+}
+
+// SpanStatus represents a span status.
+type SpanStatus struct {
+	Code        string `json:"Code"`
+	Description string `json:"Description"`
+}
+
+// SpanLink represents a span link.
+type SpanLink struct {
+	SpanContext SpanContext `json:"SpanContext"`
+}
+
+// SpanEvent represents a span event.
+type SpanEvent struct {
+	Name       string          `json:"Name"`
+	Attributes []SpanAttribute `json:"Attributes"`
 }
 
 // SpanContext represents a span context.
@@ -39,11 +39,14 @@ func Register(mocks ...func(m Sqlmock)) Sqlmocker {
 			mock(m)
 		}
 
-		tb.Cleanup(func() {
-			assert.NoError(tb, m.ExpectationsWereMet())
+		// The connection is closed either by the database under test or by the cleanup below.
+		m.ExpectClose()
 
-			// We do not care if closing mock fails.
+		tb.Cleanup(func() {
+			// We do not care if closing mock fails, it is already closed if the database under test is closed.
 			_ = mockDB.Close() // nolint: errcheck
+
+			assert.NoError(tb, m.ExpectationsWereMet())
 		})
 
 		return getDSN(m)
@@ -1,5 +1,10 @@
 package otelsql
 
+import (
+	"database/sql/driver"
+	"errors"
+)
+
 type middleware[T any] func(next T) T
 
 // chainMiddlewares builds an inline middleware stack in the order they are passed.
@@ -18,3 +23,11 @@ func chainMiddlewares[T any](middlewares []middleware[T], last T) T {
 
 	return h
 }
+
+// observeUnlessSkipped calls f unless the call returned driver.ErrSkip. The skipped calls are executed again by
+// database/sql with a prepared statement, so observing them would count the same call twice.
+func observeUnlessSkipped(err error, f func()) {
+	if !errors.Is(err, driver.ErrSkip) {
+		f()
+	}
+}
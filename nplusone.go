@@ -0,0 +1,348 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// eventNPlusOne is the name of the event added to the parent span when the N+1 queries are detected.
+	eventNPlusOne = "db.n_plus_one"
+
+	defaultNPlusOneThreshold       = 10
+	defaultNPlusOneMaxTraces       = 1000
+	defaultNPlusOneMaxFingerprints = 100
+
+	// nPlusOneMaxParents is the maximum number of parent spans tracked per trace.
+	nPlusOneMaxParents = 100
+)
+
+// NPlusOne describes a query executed more times than the threshold within a parent span.
+type NPlusOne struct {
+	// TraceID is the id of the trace of the parent span.
+	TraceID trace.TraceID
+	// ParentSpanID is the id of the span in which the query is executed.
+	ParentSpanID trace.SpanID
+	// Fingerprint identifies the shape of the query.
+	Fingerprint string
+	// Query is the query that exceeded the threshold.
+	Query string
+	// Count is the number of executions of the query within the parent span.
+	Count int
+}
+
+// NPlusOneOption allows for managing the detection of the N+1 queries using functional options.
+type NPlusOneOption interface {
+	applyNPlusOneOptions(o *nPlusOneOptions)
+}
+
+// nPlusOneOptions holds the configuration of the detection of the N+1 queries.
+type nPlusOneOptions struct {
+	// threshold is the number of executions of a query within a parent span above which the queries are reported.
+	threshold int
+
+	// maxTraces is the maximum number of traces tracked at the same time.
+	maxTraces int
+
+	// maxFingerprints is the maximum number of fingerprints counted per parent span.
+	maxFingerprints int
+
+	// callback is called when the N+1 queries are detected.
+	callback func(ctx context.Context, e NPlusOne)
+}
+
+// nPlusOneParent counts the executions of the queries within a parent span. The span is not retained, it is ended
+// once the contexts of its queries are done.
+type nPlusOneParent struct {
+	counts   map[string]int
+	ended    bool
+	stop     func() bool
+	lastSeen uint64
+}
+
+// nPlusOneTrace holds the parent spans of a trace.
+type nPlusOneTrace struct {
+	parents  map[trace.SpanID]*nPlusOneParent
+	lastSeen uint64
+}
+
+// ended checks whether all the parent spans of the trace have ended.
+func (t *nPlusOneTrace) ended() bool {
+	for _, p := range t.parents {
+		if !p.ended {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evict removes the parent spans that have ended, or the least recently seen one, to make room for a new parent span.
+func (t *nPlusOneTrace) evict() {
+	if len(t.parents) < nPlusOneMaxParents {
+		return
+	}
+
+	for id, p := range t.parents {
+		if p.ended {
+			delete(t.parents, id)
+		}
+	}
+
+	if len(t.parents) < nPlusOneMaxParents {
+		return
+	}
+
+	var (
+		oldestID trace.SpanID
+		oldest   *nPlusOneParent
+	)
+
+	for id, p := range t.parents {
+		if oldest == nil || p.lastSeen < oldest.lastSeen {
+			oldestID, oldest = id, p
+		}
+	}
+
+	oldest.stop()
+	delete(t.parents, oldestID)
+}
+
+// nPlusOneDetector counts the executions of the queries by fingerprint within their parent spans, and reports the
+// queries executed more times than the threshold.
+type nPlusOneDetector struct {
+	threshold       int
+	maxTraces       int
+	maxFingerprints int
+	callback        func(ctx context.Context, e NPlusOne)
+
+	countDetections int64Counter
+	attributesSet   attribute.Set
+
+	mu     sync.Mutex
+	traces map[trace.TraceID]*nPlusOneTrace
+	seq    uint64
+}
+
+// Observe counts the execution of the query within the parent span of the context, if it is recording.
+func (d *nPlusOneDetector) Observe(ctx context.Context, query string) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	sc := span.SpanContext()
+	fingerprint := queryFingerprint(query)
+
+	if count := d.count(ctx, sc, fingerprint); count != d.threshold+1 {
+		return
+	}
+
+	// The queries are only reported once per parent span, when they exceed the threshold.
+	e := NPlusOne{
+		TraceID:      sc.TraceID(),
+		ParentSpanID: sc.SpanID(),
+		Fingerprint:  fingerprint,
+		Query:        query,
+		Count:        d.threshold + 1,
+	}
+
+	span.AddEvent(eventNPlusOne, trace.WithAttributes(
+		dbSQLQueryFingerprint.String(fingerprint),
+		dbSQLNPlusOneCount.Int(e.Count),
+		dbSQLNPlusOneThreshold.Int(d.threshold),
+	))
+
+	// The fingerprint is only on the event, it would make the cardinality of the metric unbounded.
+	d.countDetections(ctx, 1, metric.WithAttributeSet(d.attributesSet))
+
+	if d.callback != nil {
+		d.callback(ctx, e)
+	}
+}
+
+// count increases and returns the number of executions of the query within the parent span. The queries with a new
+// fingerprint are not counted once the parent span has reached the maximum number of fingerprints.
+func (d *nPlusOneDetector) count(ctx context.Context, sc trace.SpanContext, fingerprint string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.seq++
+
+	t, ok := d.traces[sc.TraceID()]
+	if !ok {
+		d.evict()
+
+		t = &nPlusOneTrace{parents: make(map[trace.SpanID]*nPlusOneParent)}
+		d.traces[sc.TraceID()] = t
+	}
+
+	t.lastSeen = d.seq
+
+	p, ok := t.parents[sc.SpanID()]
+	if !ok {
+		t.evict()
+
+		p = &nPlusOneParent{counts: make(map[string]int), ended: true}
+		t.parents[sc.SpanID()] = p
+	}
+
+	p.lastSeen = d.seq
+
+	// The parent span ends with the context of its queries, it is seen again if the queries have their own contexts.
+	if p.ended {
+		p.ended = false
+		p.stop = context.AfterFunc(ctx, func() {
+			d.end(p)
+		})
+	}
+
+	if _, ok := p.counts[fingerprint]; !ok && len(p.counts) >= d.maxFingerprints {
+		return 0
+	}
+
+	p.counts[fingerprint]++
+
+	return p.counts[fingerprint]
+}
+
+// end marks the parent span as ended.
+func (d *nPlusOneDetector) end(p *nPlusOneParent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p.ended = true
+}
+
+// evict removes the traces that have ended, or the least recently seen one, to make room for a new trace.
+func (d *nPlusOneDetector) evict() {
+	if len(d.traces) < d.maxTraces {
+		return
+	}
+
+	for id, t := range d.traces {
+		if t.ended() {
+			delete(d.traces, id)
+		}
+	}
+
+	if len(d.traces) < d.maxTraces {
+		return
+	}
+
+	var (
+		oldestID trace.TraceID
+		oldest   *nPlusOneTrace
+	)
+
+	for id, t := range d.traces {
+		if oldest == nil || t.lastSeen < oldest.lastSeen {
+			oldestID, oldest = id, t
+		}
+	}
+
+	for _, p := range oldest.parents {
+		p.stop()
+	}
+
+	delete(d.traces, oldestID)
+}
+
+func newNPlusOneDetector(opts nPlusOneOptions, detectionsCounter int64Counter, attrs ...attribute.KeyValue) *nPlusOneDetector {
+	d := &nPlusOneDetector{
+		threshold:       opts.threshold,
+		maxTraces:       opts.maxTraces,
+		maxFingerprints: opts.maxFingerprints,
+		callback:        opts.callback,
+		countDetections: detectionsCounter,
+		attributesSet:   attribute.NewSet(attrs...),
+		traces:          make(map[trace.TraceID]*nPlusOneTrace),
+	}
+
+	if d.threshold <= 0 {
+		d.threshold = defaultNPlusOneThreshold
+	}
+
+	if d.maxTraces <= 0 {
+		d.maxTraces = defaultNPlusOneMaxTraces
+	}
+
+	if d.maxFingerprints <= 0 {
+		d.maxFingerprints = defaultNPlusOneMaxFingerprints
+	}
+
+	return d
+}
+
+// execDetectNPlusOne counts the executions of the query within the parent span.
+func execDetectNPlusOne(d *nPlusOneDetector) execContextFuncMiddleware {
+	return func(next execContextFunc) execContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			result, err := next(ctx, query, args)
+
+			observeUnlessSkipped(err, func() {
+				d.Observe(ctx, query)
+			})
+
+			return result, err
+		}
+	}
+}
+
+// queryDetectNPlusOne counts the executions of the query within the parent span.
+func queryDetectNPlusOne(d *nPlusOneDetector) queryContextFuncMiddleware {
+	return func(next queryContextFunc) queryContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+			result, err := next(ctx, query, args)
+
+			observeUnlessSkipped(err, func() {
+				d.Observe(ctx, query)
+			})
+
+			return result, err
+		}
+	}
+}
+
+type nPlusOneOptionFunc func(o *nPlusOneOptions)
+
+func (f nPlusOneOptionFunc) applyNPlusOneOptions(o *nPlusOneOptions) {
+	f(o)
+}
+
+// NPlusOneThreshold sets the number of executions of a query within a parent span above which the queries are reported.
+// The default is 10.
+func NPlusOneThreshold(n int) NPlusOneOption {
+	return nPlusOneOptionFunc(func(o *nPlusOneOptions) {
+		o.threshold = n
+	})
+}
+
+// NPlusOneMaxTraces sets the maximum number of traces tracked at the same time. When a new trace starts, the traces
+// whose parent spans have ended are evicted first, then the least recently seen one. A parent span is considered ended
+// when the contexts of its queries are done. The default is 1000.
+func NPlusOneMaxTraces(n int) NPlusOneOption {
+	return nPlusOneOptionFunc(func(o *nPlusOneOptions) {
+		o.maxTraces = n
+	})
+}
+
+// NPlusOneMaxFingerprints sets the maximum number of fingerprints counted per parent span. The queries with a new
+// fingerprint are not counted once the maximum is reached. The default is 100.
+func NPlusOneMaxFingerprints(n int) NPlusOneOption {
+	return nPlusOneOptionFunc(func(o *nPlusOneOptions) {
+		o.maxFingerprints = n
+	})
+}
+
+// OnNPlusOne sets a callback that is called when the N+1 queries are detected, once per query and parent span.
+func OnNPlusOne(f func(ctx context.Context, e NPlusOne)) NPlusOneOption {
+	return nPlusOneOptionFunc(func(o *nPlusOneOptions) {
+		o.callback = f
+	})
+}
@@ -0,0 +1,205 @@
+package otelsql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestNPlusOneDetector(opts nPlusOneOptions, detections *int64) *nPlusOneDetector {
+	return newNPlusOneDetector(opts, func(_ context.Context, value int64, _ ...metric.AddOption) {
+		*detections += value
+	})
+}
+
+func TestNPlusOneDetector_Observe(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)).Tracer(t.Name())
+
+	var (
+		detections int64
+		events     []NPlusOne
+	)
+
+	d := newTestNPlusOneDetector(nPlusOneOptions{
+		threshold: 2,
+		callback: func(_ context.Context, e NPlusOne) {
+			events = append(events, e)
+		},
+	}, &detections)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	otherCtx, other := tracer.Start(ctx, "other")
+
+	for i := 0; i < 5; i++ {
+		d.Observe(ctx, "SELECT * FROM orders WHERE user_id = ?")
+	}
+
+	d.Observe(ctx, "SELECT * FROM users")
+	d.Observe(otherCtx, "SELECT * FROM orders WHERE user_id = ?")
+
+	// The spans that are not recording are not counted.
+	for i := 0; i < 5; i++ {
+		d.Observe(context.Background(), "SELECT * FROM orders WHERE user_id = ?")
+	}
+
+	other.End()
+	parent.End()
+
+	assert.Equal(t, int64(1), detections)
+	require.Len(t, events, 1)
+	assert.Equal(t, 3, events[0].Count)
+	assert.Equal(t, parent.SpanContext().TraceID(), events[0].TraceID)
+	assert.Equal(t, parent.SpanContext().SpanID(), events[0].ParentSpanID)
+	assert.Equal(t, queryFingerprint("SELECT * FROM orders WHERE user_id = 1"), events[0].Fingerprint)
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+
+	assert.Empty(t, ended[0].Events())
+	require.Len(t, ended[1].Events(), 1)
+	assert.Equal(t, eventNPlusOne, ended[1].Events()[0].Name)
+}
+
+func TestNPlusOneDetector_Observe_MetricAttributes(t *testing.T) {
+	t.Parallel()
+
+	tracer := tracesdk.NewTracerProvider().Tracer(t.Name())
+
+	var actual []attribute.Set
+
+	d := newNPlusOneDetector(nPlusOneOptions{threshold: 1}, func(_ context.Context, _ int64, opts ...metric.AddOption) {
+		actual = append(actual, metric.NewAddConfig(opts).Attributes())
+	}, semconv.DBSystemPostgreSQL)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+
+	d.Observe(ctx, "SELECT * FROM orders WHERE user_id = 1")
+	d.Observe(ctx, "SELECT * FROM orders WHERE user_id = 2")
+
+	parent.End()
+
+	// The fingerprint is not a metric attribute.
+	expected := []attribute.Set{attribute.NewSet(semconv.DBSystemPostgreSQL)}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestNPlusOneDetector_Evict(t *testing.T) {
+	t.Parallel()
+
+	tracer := tracesdk.NewTracerProvider().Tracer(t.Name())
+
+	var detections int64
+
+	d := newTestNPlusOneDetector(nPlusOneOptions{maxTraces: 2}, &detections)
+
+	start := func() (context.Context, context.CancelFunc, trace.Span) {
+		ctx, span := tracer.Start(context.Background(), "parent")
+		ctx, cancel := context.WithCancel(ctx)
+
+		return ctx, cancel, span
+	}
+
+	ctx1, cancel1, span1 := start()
+	ctx2, cancel2, span2 := start()
+	ctx3, cancel3, span3 := start()
+
+	d.Observe(ctx1, "SELECT 1")
+	d.Observe(ctx2, "SELECT 1")
+
+	// The ended traces are evicted first.
+	cancel1()
+	requireNPlusOneParentEnded(t, d, span1.SpanContext())
+
+	d.Observe(ctx3, "SELECT 1")
+
+	assert.NotContains(t, d.traces, span1.SpanContext().TraceID())
+	assert.Contains(t, d.traces, span2.SpanContext().TraceID())
+	assert.Contains(t, d.traces, span3.SpanContext().TraceID())
+
+	// Then the least recently seen one.
+	d.Observe(ctx2, "SELECT 1")
+
+	ctx4, cancel4, span4 := start()
+	d.Observe(ctx4, "SELECT 1")
+
+	assert.NotContains(t, d.traces, span3.SpanContext().TraceID())
+	assert.Contains(t, d.traces, span2.SpanContext().TraceID())
+	assert.Contains(t, d.traces, span4.SpanContext().TraceID())
+
+	cancel2()
+	cancel3()
+	cancel4()
+}
+
+func TestNPlusOneDetector_QueryContexts(t *testing.T) {
+	t.Parallel()
+
+	tracer := tracesdk.NewTracerProvider().Tracer(t.Name())
+
+	var detections int64
+
+	d := newTestNPlusOneDetector(nPlusOneOptions{threshold: 2}, &detections)
+
+	ctx, span := tracer.Start(context.Background(), "parent")
+	defer span.End()
+
+	// The parent span is seen again when each query has its own context.
+	for i := 0; i < 3; i++ {
+		queryCtx, cancel := context.WithCancel(ctx)
+
+		d.Observe(queryCtx, "SELECT * FROM orders WHERE user_id = ?")
+		cancel()
+
+		requireNPlusOneParentEnded(t, d, span.SpanContext())
+	}
+
+	assert.Equal(t, int64(1), detections)
+}
+
+func TestNPlusOneDetector_MaxFingerprints(t *testing.T) {
+	t.Parallel()
+
+	tracer := tracesdk.NewTracerProvider().Tracer(t.Name())
+
+	var detections int64
+
+	d := newTestNPlusOneDetector(nPlusOneOptions{threshold: 1, maxFingerprints: 1}, &detections)
+
+	ctx, span := tracer.Start(context.Background(), "parent")
+	defer span.End()
+
+	for i := 0; i < 2; i++ {
+		d.Observe(ctx, "SELECT * FROM orders")
+		d.Observe(ctx, "SELECT * FROM users")
+	}
+
+	p := d.traces[span.SpanContext().TraceID()].parents[span.SpanContext().SpanID()]
+
+	assert.Equal(t, map[string]int{queryFingerprint("SELECT * FROM orders"): 2}, p.counts)
+	assert.Equal(t, int64(1), detections)
+}
+
+func requireNPlusOneParentEnded(t *testing.T, d *nPlusOneDetector, sc trace.SpanContext) {
+	t.Helper()
+
+	// The parent span is ended asynchronously, after the context is done.
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		return d.traces[sc.TraceID()].parents[sc.SpanID()].ended
+	}, time.Second, time.Millisecond)
+}
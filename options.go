@@ -37,13 +37,43 @@ type driverOptions struct {
 
 	// defaultAttributes will be set to each span and metrics as default.
 	defaultAttributes []attribute.KeyValue
+
+	// driverName is the name used by Register, instead of a generated one.
+	driverName string
+
+	// countRows counts the rows and the bytes returned by queries.
+	countRows bool
+
+	// recordResponseTime records the time to the first row and the duration of the responses of queries.
+	recordResponseTime bool
+
+	// rowsAffected records the rows affected by execs.
+	rowsAffected rowsAffectedMode
+
+	// prepareFingerprint labels the prepare counter with the fingerprint of the queries.
+	prepareFingerprint bool
+
+	// errorPolicies decide the status of the failed calls in the spans and in the metrics.
+	errorPolicies errorPolicies
+
+	// detectNPlusOne and nPlusOne detect the queries executed more times than a threshold within their parent spans.
+	detectNPlusOne bool
+	nPlusOne       nPlusOneOptions
+
+	// recordStats and statsOptions are used by OpenDB to record the database connection metrics.
+	recordStats  bool
+	statsOptions []StatsOption
 }
 
 // TraceOptions are options to enable the creations of spans on sql calls.
 type TraceOptions struct {
-	spanNameFormatter spanNameFormatter
-	errorToSpanStatus errorToSpanStatus
-	queryTracer       queryTracer
+	spanNameFormatter        spanNameFormatter
+	errorToSpanStatus        errorToSpanStatus
+	errorToSpanStatusContext errorToSpanStatusContext
+	disableErrSkip           bool
+	ignoredSQLErrors         []func(SQLError) bool
+	queryTracer              queryTracer
+	caller                   callerOptions
 
 	// AllowRoot, if set to true, will allow otelsql to create root spans in absence of existing spans or even context.
 	//
@@ -59,11 +89,64 @@ type TraceOptions struct {
 	// RowsClose, if set to true, will enable the creation of spans on RowsClose calls.
 	RowsClose bool
 
+	// RowsResultSets, if set to true, will enable the creation of a span per result set of the rows, if the driver
+	// supports multiple result sets and the rows have more than one.
+	RowsResultSets bool
+
+	// RowsNextSummary, if set to true, will add the count of the rows, the latency of the first row, the iteration time
+	// and the maximum gap between two rows to the query spans. The query spans end when the rows are closed.
+	RowsNextSummary bool
+
+	// QueryUntilRowsClose, if set to true, will end the query spans when the rows are closed instead of when the queries
+	// return, with the time to the first row and the duration of the response.
+	QueryUntilRowsClose bool
+
 	// RowsAffected, if set to true, will enable the creation of spans on RowsAffected calls.
 	RowsAffected bool
 
 	// LastInsertID, if set to true, will enable the creation of spans on LastInsertId calls.
 	LastInsertID bool
+
+	// ResultEvents, if set to true, will add events instead of creating spans on LastInsertId and RowsAffected calls.
+	ResultEvents bool
+
+	// Connection, if set to true, will add the id, the age and the use count of the connection to the spans.
+	Connection bool
+
+	// Statement, if set to true, will link the spans of the executions of the prepared statements to the prepare spans,
+	// and add the age and the use count of the statements.
+	Statement bool
+
+	// SQLErrors, if set to true, will add the error.type of the errors, and the db.response.status_code of the errors
+	// returned by the database, to the spans of the failed calls.
+	SQLErrors bool
+
+	// Caller, if set to true, will add the location of the code that called the methods to the spans.
+	Caller bool
+
+	// ConnClose, if set to true, will enable the creation of spans on Conn.Close calls.
+	ConnClose bool
+
+	// StmtClose, if set to true, will enable the creation of spans on Stmt.Close calls.
+	StmtClose bool
+
+	// ResetSession, if set to true, will enable the creation of spans on ResetSession calls.
+	ResetSession bool
+}
+
+// errorToStatus returns the converter of the errors to the span status. The plain converter is wrapped, so that
+// DisableErrSkip applies to both kinds of converters.
+func (o TraceOptions) errorToStatus() errorToSpanStatusContext {
+	f := o.errorToSpanStatusContext
+	if f == nil {
+		f = o.errorToSpanStatus.withContext()
+	}
+
+	if o.disableErrSkip {
+		return ignoreErrSkip(f)
+	}
+
+	return f
 }
 
 // WithMeterProvider sets meter provider.
@@ -126,16 +209,59 @@ func WithSpanNameFormatter(f spanNameFormatter) DriverOption {
 	})
 }
 
-// ConvertErrorToSpanStatus sets a custom error converter.
+// ConvertErrorToSpanStatus sets a custom error converter. It replaces the converter set by
+// ConvertErrorToSpanStatusContext, if any.
 func ConvertErrorToSpanStatus(f errorToSpanStatus) DriverOption {
 	return driverOptionFunc(func(o *driverOptions) {
 		o.trace.errorToSpanStatus = f
+		o.trace.errorToSpanStatusContext = nil
 	})
 }
 
-// DisableErrSkip suppresses driver.ErrSkip errors in spans if set to true.
+// ConvertErrorToSpanStatusContext sets a custom error converter that receives the context of the span, for example to
+// get the OperationInfo of the method. It replaces the converter set by ConvertErrorToSpanStatus, if any.
+func ConvertErrorToSpanStatusContext(f errorToSpanStatusContext) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.errorToSpanStatus = nil
+		o.trace.errorToSpanStatusContext = f
+	})
+}
+
+// DisableErrSkip suppresses driver.ErrSkip errors in spans if set to true, whatever the error converter and the order
+// of the options.
 func DisableErrSkip() DriverOption {
-	return ConvertErrorToSpanStatus(spanStatusFromErrorIgnoreErrSkip)
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.disableErrSkip = true
+	})
+}
+
+// WithErrorPolicy adds policies that decide the status of the failed calls, consistently in the span status, the
+// recorded errors of the spans and the db.sql.status of the metrics. The policies are applied in order until one
+// recognizes the error, the errors that no policy recognizes are failures. For example:
+//
+//	otelsql.WithErrorPolicy(otelsql.IgnoreContextCanceled, otelsql.IgnoreErrSkip)
+func WithErrorPolicy(policies ...ErrorPolicy) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.errorPolicies = append(o.errorPolicies, policies...)
+	})
+}
+
+// IgnoreSQLErrors considers the errors returned by the database as OK in span status if any of the matchers matches
+// them, see InspectError. The errors are still recorded as errors by the metrics.
+func IgnoreSQLErrors(matchers ...func(SQLError) bool) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.ignoredSQLErrors = append(o.trace.ignoredSQLErrors, matchers...)
+	})
+}
+
+// IgnoreUniqueViolations considers the violations of unique constraints as OK in span status.
+func IgnoreUniqueViolations() DriverOption {
+	return IgnoreSQLErrors(SQLError.IsUniqueViolation)
+}
+
+// IgnoreSerializationFailures considers the serialization failures and the deadlocks as OK in span status.
+func IgnoreSerializationFailures() DriverOption {
+	return IgnoreSQLErrors(SQLError.IsSerializationFailure)
 }
 
 // TraceQuery sets a custom function that will return a list of attributes to add to the spans with a given query and args.
@@ -170,7 +296,13 @@ func TraceQueryWithoutArgs() DriverOption {
 	return TraceQuery(traceQueryWithoutArgs)
 }
 
-// TraceAll enables the creation of spans on methods.
+// TraceAll enables the creation of spans on methods: it allows the root spans, adds the queries with their arguments,
+// and traces the pings, the iterations and the closes of the rows, the rows affected and the last insert ids.
+//
+// The connections, the statements, the SQL errors, the caller, and the spans of the result sets, Conn.Close, Stmt.Close
+// and ResetSession stay opt-in, see TraceConnection, TraceStatement, TraceSQLErrors, TraceCaller, TraceRowsResultSets,
+// TraceConnClose, TraceStmtClose and TraceResetSession. They add spans to every call or capture its stack, which is
+// costly, or add attributes with many distinct values, like the ids and the error messages, which raise the cardinality.
 func TraceAll() DriverOption {
 	return driverOptionFunc(func(o *driverOptions) {
 		o.trace.queryTracer = traceQueryWithArgs
@@ -213,6 +345,34 @@ func TraceRowsClose() DriverOption {
 	})
 }
 
+// TraceRowsResultSets enables the creation of a span per result set of the rows, for the drivers that support multiple
+// result sets, like the stored procedures of SQL Server and MySQL. The spans have the index and the row count of the
+// result set, and the error if switching to the next result set fails. The rows with a single result set have no such
+// span, the spans are created from the first successful switch to the next result set.
+func TraceRowsResultSets() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.RowsResultSets = true
+	})
+}
+
+// TraceRowsNextSummary adds the summary of the iteration of the rows to the query spans instead of creating a span per
+// row: the count of the rows, the latency of the first row since the query started, the time since the query returned
+// until the iteration ended, and the maximum gap between two rows. The query spans end when the rows are closed.
+func TraceRowsNextSummary() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.RowsNextSummary = true
+	})
+}
+
+// TraceQueryUntilRowsClose ends the query spans when the rows are closed instead of when the queries return, so that
+// they cover the consumption of the rows. The spans have the time to the first row and the time since the query started
+// until the rows were consumed or closed, and the status of the first error of the iteration or of the close.
+func TraceQueryUntilRowsClose() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.QueryUntilRowsClose = true
+	})
+}
+
 // TraceRowsAffected enables the creation of spans on RowsAffected calls.
 func TraceRowsAffected() DriverOption {
 	return driverOptionFunc(func(o *driverOptions) {
@@ -227,6 +387,173 @@ func TraceLastInsertID() DriverOption {
 	})
 }
 
+// TraceResultEvents adds the last_insert_id and rows_affected events on LastInsertId and RowsAffected calls, with their
+// result and latency, instead of creating spans. The events are added to the parent span of the exec, because the exec
+// span has ended by then. When the exec has no parent span, with AllowRoot, or the parent span has ended too, the spans
+// are created instead.
+func TraceResultEvents() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.ResultEvents = true
+	})
+}
+
+// CountRows counts the rows and the approximate size of the values, the sum of the lengths of []byte and string, returned
+// by queries. The totals are recorded in the db.client.response.returned_rows and db.client.response.returned_bytes
+// histograms and added to the query spans when the rows are closed, so the query spans end when the rows are closed
+// instead of when the query returns.
+func CountRows() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.countRows = true
+	})
+}
+
+// RecordResponseTime records the time since queries started until they returned the first row, and until their rows were
+// consumed or closed, in the db.client.response.time_to_first_row and db.client.response.duration histograms. The
+// query spans end when the rows are closed instead of when the query returns.
+//
+// The time is also recorded with TraceQueryUntilRowsClose(), TraceRowsNextSummary() or CountRows().
+func RecordResponseTime() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.recordResponseTime = true
+	})
+}
+
+// RecordRowsAffected calls RowsAffected as soon as an exec returns, and records the result in the
+// db.client.rows_affected histogram and in the db.sql.rows_affected attribute of the exec span. The result is cached,
+// so the driver is called only once. Use it with the drivers that know the rows affected without another round trip.
+func RecordRowsAffected() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.rowsAffected = rowsAffectedEager
+	})
+}
+
+// RecordRowsAffectedLazily records the rows affected in the db.client.rows_affected histogram when the caller calls
+// RowsAffected, if ever. The exec span has already ended by then, so it does not have the rows affected.
+func RecordRowsAffectedLazily() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.rowsAffected = rowsAffectedLazy
+	})
+}
+
+// RecordPrepareFingerprint adds the db.sql.query.fingerprint of the queries to the db.client.statement.prepares counter,
+// to find the queries that are prepared again on every call. The queries that only differ by their literals have the
+// same fingerprint, but the number of distinct queries of the application must be bounded.
+func RecordPrepareFingerprint() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.prepareFingerprint = true
+	})
+}
+
+// TraceConnection adds the id, the age and the use count of the connection to the spans. The first span after the
+// connection is handed out also has the time the call waited for it, see ContextWithAcquireStart.
+func TraceConnection() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.Connection = true
+	})
+}
+
+// TraceStatement links the spans of the executions of the prepared statements to their prepare spans, so that the
+// statements prepared by a request and executed by another are connected. The spans also have the age and the use count
+// of the statements.
+func TraceStatement() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.Statement = true
+	})
+}
+
+// TraceSQLErrors adds the error.type of the errors, and the db.response.status_code of the errors returned by the
+// database, to the spans of the failed calls. See InspectError.
+func TraceSQLErrors() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.SQLErrors = true
+	})
+}
+
+// TraceCaller adds the location of the code that called the methods to the spans, as code.function, code.filepath and
+// code.lineno: the first frame that belongs neither to database/sql nor to otelsql, nor to the packages skipped by
+// SkipCallerPackages(). The caller is only captured for the sampled spans. For example:
+//
+//	otelsql.TraceCaller(
+//		otelsql.SkipCallerPackages("github.com/jmoiron/sqlx"),
+//		otelsql.CallerMethods("query", "exec"),
+//		otelsql.CallerStackTrace(),
+//	)
+func TraceCaller(opts ...CallerOption) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.Caller = true
+
+		for _, opt := range opts {
+			opt.applyCallerOptions(&o.trace.caller)
+		}
+	})
+}
+
+// TraceConnClose enables the creation of spans on Conn.Close calls.
+//
+// Closing a connection does not take a context, so the spans are only created with AllowRoot().
+func TraceConnClose() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.ConnClose = true
+	})
+}
+
+// TraceStmtClose enables the creation of spans on Stmt.Close calls.
+//
+// Closing a statement does not take a context, so the spans are only created with AllowRoot().
+func TraceStmtClose() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.StmtClose = true
+	})
+}
+
+// TraceResetSession enables the creation of spans on ResetSession calls.
+func TraceResetSession() DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.trace.ResetSession = true
+	})
+}
+
+// DetectNPlusOne detects the N+1 queries: the queries with the same fingerprint executed more times than a threshold
+// within the same parent span, usually in a loop. When a query exceeds the threshold, a db.n_plus_one event is added to
+// the parent span, the db.client.n_plus_one counter is increased and the OnNPlusOne() callback is called. The queries
+// are only counted when their parent span is recording. For example:
+//
+//	otelsql.DetectNPlusOne(
+//		otelsql.NPlusOneThreshold(20),
+//		otelsql.OnNPlusOne(func(ctx context.Context, e otelsql.NPlusOne) {
+//			slog.WarnContext(ctx, "N+1 queries", "query", e.Query, "count", e.Count)
+//		}),
+//	)
+func DetectNPlusOne(opts ...NPlusOneOption) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.detectNPlusOne = true
+
+		for _, opt := range opts {
+			opt.applyNPlusOneOptions(&o.nPlusOne)
+		}
+	})
+}
+
+// WithDriverName sets the name of the driver registered by Register, instead of generating one. Registering the same
+// name again returns an error, unless the driver and the options are identical.
+func WithDriverName(name string) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.driverName = name
+	})
+}
+
+// WithRecordStats records the database connection metrics of the database opened by OpenDB. The meter provider and the
+// default attributes of the driver are used, the opts are applied after them.
+//
+// Only OpenDB opens the database, so the option is ignored by Register, RegisterWithSource, Wrap and WrapConnector, and
+// does not prevent the reuse of a registered driver name. Use RecordStats with the opened database instead.
+func WithRecordStats(opts ...StatsOption) DriverOption {
+	return driverOptionFunc(func(o *driverOptions) {
+		o.recordStats = true
+		o.statsOptions = append(o.statsOptions, opts...)
+	})
+}
+
 // WithMinimumReadDBStatsInterval sets the minimum interval between calls to db.Stats(). Negative values are ignored.
 func WithMinimumReadDBStatsInterval(interval time.Duration) StatsOption {
 	return statsOptionFunc(func(o *statsOptions) {
@@ -234,6 +561,21 @@ func WithMinimumReadDBStatsInterval(interval time.Duration) StatsOption {
 	})
 }
 
+// WithPoolName sets the db.client.connection.pool.name attribute of the database connection metrics.
+func WithPoolName(name string) StatsOption {
+	return statsOptionFunc(func(o *statsOptions) {
+		o.defaultAttributes = append(o.defaultAttributes, dbClientConnectionPoolName.String(name))
+	})
+}
+
+// WithPoolAnalyzer analyzes the statistics of the pools to detect their saturation and suggest their settings. The
+// saturation of the analyzed pools is also recorded as db.client.connection.pool.saturation.
+func WithPoolAnalyzer(a *PoolAnalyzer) StatsOption {
+	return statsOptionFunc(func(o *statsOptions) {
+		o.analyzer = a
+	})
+}
+
 type statsOptions struct {
 	// meterProvider sets the metric.MeterProvider. If nil, the global Provider will be used.
 	meterProvider metric.MeterProvider
@@ -243,6 +585,9 @@ type statsOptions struct {
 
 	// defaultAttributes will be set to each metrics as default.
 	defaultAttributes []attribute.KeyValue
+
+	// analyzer analyzes the statistics of the pools, if not nil.
+	analyzer *PoolAnalyzer
 }
 
 type driverOptionFunc func(o *driverOptions)
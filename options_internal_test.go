@@ -24,6 +24,16 @@ func TestTraceAll(t *testing.T) {
 	assert.True(t, o.trace.RowsAffected)
 	assert.True(t, o.trace.LastInsertID)
 
+	// The other options are opt-in.
+	assert.False(t, o.trace.RowsResultSets)
+	assert.False(t, o.trace.Connection)
+	assert.False(t, o.trace.Statement)
+	assert.False(t, o.trace.SQLErrors)
+	assert.False(t, o.trace.Caller)
+	assert.False(t, o.trace.ConnClose)
+	assert.False(t, o.trace.StmtClose)
+	assert.False(t, o.trace.ResetSession)
+
 	var (
 		ctx    = context.Background()
 		query  = "SELECT * FROM data WHERE country = $1"
@@ -73,14 +83,59 @@ func TestDisableErrSkip(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			o := driverOptions{}
-
-			DisableErrSkip().applyDriverOptions(&o)
+			o := newDriverOptions(DisableErrSkip())
 
-			code, description := o.trace.errorToSpanStatus(tc.error)
+			code, description := o.trace.errorToStatus()(context.Background(), tc.error)
 
 			assert.Equal(t, tc.expectedCode, code)
 			assert.Equal(t, tc.expectedDescription, description)
 		})
 	}
 }
+
+func TestDisableErrSkip_ConvertErrorToSpanStatus(t *testing.T) {
+	t.Parallel()
+
+	convert := func(_ context.Context, _ error) (codes.Code, string) {
+		return codes.Error, "converted"
+	}
+
+	testCases := []struct {
+		scenario string
+		options  []DriverOption
+	}{
+		{
+			scenario: "disable before",
+			options:  []DriverOption{DisableErrSkip(), ConvertErrorToSpanStatusContext(convert)},
+		},
+		{
+			scenario: "disable after",
+			options:  []DriverOption{ConvertErrorToSpanStatusContext(convert), DisableErrSkip()},
+		},
+		{
+			scenario: "plain converter",
+			options: []DriverOption{DisableErrSkip(), ConvertErrorToSpanStatus(func(err error) (codes.Code, string) {
+				return convert(context.Background(), err)
+			})},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			errorToStatus := newDriverOptions(tc.options...).trace.errorToStatus()
+
+			code, description := errorToStatus(context.Background(), driver.ErrSkip)
+
+			assert.Equal(t, codes.Ok, code)
+			assert.Empty(t, description)
+
+			code, description = errorToStatus(context.Background(), errors.New("error"))
+
+			assert.Equal(t, codes.Error, code)
+			assert.Equal(t, "converted", description)
+		})
+	}
+}
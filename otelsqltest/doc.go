@@ -0,0 +1,3 @@
+// Package otelsqltest provides an in-memory recorder of the spans and the metrics of the otelsql drivers, and helpers to
+// assert on the queries issued by the code under test.
+package otelsqltest
@@ -0,0 +1,298 @@
+package otelsqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.nhat.io/otelsql"
+)
+
+// skippedKey marks the spans of the calls that returned driver.ErrSkip.
+const skippedKey = attribute.Key("db.sql.skipped")
+
+// queryOperations are the traced methods that issue queries.
+var queryOperations = map[string]bool{
+	"exec":  true,
+	"query": true,
+}
+
+// Option configures the Recorder.
+type Option func(r *Recorder)
+
+// WithDriverOptions adds options to the drivers wrapped by the Recorder, after the default ones.
+func WithDriverOptions(opts ...otelsql.DriverOption) Option {
+	return func(r *Recorder) {
+		r.driverOptions = append(r.driverOptions, opts...)
+	}
+}
+
+// ExpectQueryCount expects that exactly n queries are issued by the end of the test. Like QueriesIssued, it only counts
+// the queries issued since the last Reset.
+func ExpectQueryCount(n int) Option {
+	return func(r *Recorder) {
+		r.expectedQueryCount = &n
+	}
+}
+
+// Recorder wraps the drivers with otelsql and records their spans and their metrics in memory.
+//
+// The drivers trace all the calls, even without a parent span, and add the queries to the spans without their
+// arguments.
+type Recorder struct {
+	spans   *tracetest.SpanRecorder
+	metrics *metricsdk.ManualReader
+
+	tracerProvider *tracesdk.TracerProvider
+	meterProvider  *metricsdk.MeterProvider
+
+	driverOptions      []otelsql.DriverOption
+	expectedQueryCount *int
+
+	mu sync.Mutex
+	// offset is the number of ended spans ignored since the last Reset.
+	offset int
+}
+
+// New creates a Recorder. The expectations are checked and the providers are shut down when the test ends.
+func New(tb testing.TB, opts ...Option) *Recorder {
+	tb.Helper()
+
+	r := &Recorder{
+		spans:   tracetest.NewSpanRecorder(),
+		metrics: metricsdk.NewManualReader(),
+	}
+
+	r.tracerProvider = tracesdk.NewTracerProvider(
+		tracesdk.WithSampler(tracesdk.AlwaysSample()),
+		tracesdk.WithSpanProcessor(r.spans),
+	)
+	r.meterProvider = metricsdk.NewMeterProvider(metricsdk.WithReader(r.metrics))
+
+	r.driverOptions = []otelsql.DriverOption{
+		otelsql.WithTracerProvider(r.tracerProvider),
+		otelsql.WithMeterProvider(r.meterProvider),
+		otelsql.AllowRoot(),
+		otelsql.TraceQueryWithoutArgs(),
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	tb.Cleanup(func() {
+		tb.Helper()
+
+		if r.expectedQueryCount != nil {
+			r.AssertQueryCount(tb, *r.expectedQueryCount)
+		}
+
+		ctx := context.Background()
+
+		_ = r.tracerProvider.Shutdown(ctx) //nolint: errcheck
+		_ = r.meterProvider.Shutdown(ctx)  //nolint: errcheck
+	})
+
+	return r
+}
+
+// TracerProvider returns the tracer provider of the recorded spans.
+func (r *Recorder) TracerProvider() trace.TracerProvider {
+	return r.tracerProvider
+}
+
+// MeterProvider returns the meter provider of the recorded metrics.
+func (r *Recorder) MeterProvider() metric.MeterProvider {
+	return r.meterProvider
+}
+
+// DriverOptions returns the options of the drivers wrapped by the Recorder.
+func (r *Recorder) DriverOptions(opts ...otelsql.DriverOption) []otelsql.DriverOption {
+	return append(append([]otelsql.DriverOption{}, r.driverOptions...), opts...)
+}
+
+// Open opens a database with the driver registered as driverName, wrapped by the Recorder. Unlike otelsql.Register, it
+// does not register a new driver, so it can be called by as many tests as needed.
+func (r *Recorder) Open(driverName, dsn string, opts ...otelsql.DriverOption) (*sql.DB, error) {
+	// Retrieve the driver implementation to wrap.
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := db.Driver()
+
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	var c driver.Connector = dsnConnector{dsn: dsn, driver: parent}
+
+	if d, ok := parent.(driver.DriverContext); ok {
+		if c, err = d.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.OpenDB(c, opts...)
+}
+
+// Wrap wraps the driver.
+func (r *Recorder) Wrap(d driver.Driver, opts ...otelsql.DriverOption) driver.Driver {
+	return otelsql.Wrap(d, r.DriverOptions(opts...)...)
+}
+
+// OpenDB opens a database with the connector, wrapped by the Recorder.
+func (r *Recorder) OpenDB(c driver.Connector, opts ...otelsql.DriverOption) (*sql.DB, error) {
+	return otelsql.OpenDB(c, r.DriverOptions(opts...)...)
+}
+
+// dsnConnector is a driver.Connector that opens the connections of a driver.Driver with a dsn.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Spans returns the spans that have ended since the last Reset.
+func (r *Recorder) Spans() []tracesdk.ReadOnlySpan {
+	spans := r.spans.Ended()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return spans[min(r.offset, len(spans)):]
+}
+
+// Metrics collects the metrics recorded since the Recorder was created.
+func (r *Recorder) Metrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	var rm metricdata.ResourceMetrics
+
+	err := r.metrics.Collect(ctx, &rm)
+
+	return rm, err
+}
+
+// Reset ignores the spans that have ended so far, for example the ones of the setup of a test.
+func (r *Recorder) Reset() {
+	ended := len(r.spans.Ended())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.offset = ended
+}
+
+// QueriesIssued returns the queries of the execs and the queries, in the order in which their spans have ended, since
+// the last Reset. The queries are empty if the spans do not have the db.statement attribute.
+func (r *Recorder) QueriesIssued() []string {
+	var queries []string
+
+	for _, s := range r.Spans() {
+		query, ok := issuedQuery(s)
+		if !ok || skipped(s) {
+			continue
+		}
+
+		queries = append(queries, query)
+	}
+
+	return queries
+}
+
+// AssertQueryTraced asserts that the query has been issued and traced, ignoring the differences of spaces.
+func (r *Recorder) AssertQueryTraced(tb testing.TB, query string) bool {
+	tb.Helper()
+
+	expected := normalizeSpaces(query)
+	issued := r.QueriesIssued()
+
+	for _, q := range issued {
+		if normalizeSpaces(q) == expected {
+			return true
+		}
+	}
+
+	tb.Errorf("query %q has not been traced, issued queries: %q", query, issued)
+
+	return false
+}
+
+// AssertNoQueries asserts that no query has been issued.
+func (r *Recorder) AssertNoQueries(tb testing.TB) bool {
+	tb.Helper()
+
+	if issued := r.QueriesIssued(); len(issued) > 0 {
+		tb.Errorf("expected no queries, issued queries: %q", issued)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertQueryCount asserts that exactly n queries have been issued.
+func (r *Recorder) AssertQueryCount(tb testing.TB, n int) bool {
+	tb.Helper()
+
+	if issued := r.QueriesIssued(); len(issued) != n {
+		tb.Errorf("expected %d queries, issued %d queries: %q", n, len(issued), issued)
+
+		return false
+	}
+
+	return true
+}
+
+// issuedQuery returns the query of the span of an exec or a query.
+func issuedQuery(s tracesdk.ReadOnlySpan) (string, bool) {
+	var operation, query string
+
+	for _, attr := range s.Attributes() {
+		switch attr.Key {
+		case semconv.DBOperationKey:
+			operation = attr.Value.AsString()
+
+		case semconv.DBStatementKey:
+			query = attr.Value.AsString()
+		}
+	}
+
+	return query, queryOperations[operation]
+}
+
+// skipped tells whether the span of an exec or a query is of a call that returned driver.ErrSkip. database/sql executes
+// the skipped calls again with a prepared statement, so they are not issued. The spans of these calls are marked, even
+// when DisableErrSkip or IgnoreErrSkip hides the error from their status.
+func skipped(s tracesdk.ReadOnlySpan) bool {
+	for _, attr := range s.Attributes() {
+		if attr.Key == skippedKey {
+			return attr.Value.AsBool()
+		}
+	}
+
+	return false
+}
+
+func normalizeSpaces(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
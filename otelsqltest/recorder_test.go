@@ -0,0 +1,287 @@
+package otelsqltest_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.nhat.io/otelsql"
+	"go.nhat.io/otelsql/otelsqltest"
+)
+
+type fakeTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func newMock(t *testing.T, mock func(m sqlmock.Sqlmock)) string {
+	t.Helper()
+
+	dsn := t.Name()
+
+	mockDB, m, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	mock(m)
+	m.ExpectClose()
+
+	t.Cleanup(func() {
+		_ = mockDB.Close() // nolint: errcheck
+
+		assert.NoError(t, m.ExpectationsWereMet())
+	})
+
+	return dsn
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	dsn := newMock(t, func(m sqlmock.Sqlmock) {
+		m.ExpectExec(`INSERT INTO users (name) VALUES ($1)`).
+			WithArgs("John").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		m.ExpectQuery(`SELECT * FROM users WHERE name = $1`).
+			WithArgs("John").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	})
+
+	r := otelsqltest.New(t, otelsqltest.ExpectQueryCount(2))
+
+	db, err := r.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	ctx := context.Background()
+
+	r.AssertNoQueries(t)
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (name) VALUES ($1)`, "John")
+	require.NoError(t, err)
+
+	rows, err := db.QueryContext(ctx, `SELECT * FROM users WHERE name = $1`, "John")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	assert.Equal(t, []string{
+		`INSERT INTO users (name) VALUES ($1)`,
+		`SELECT * FROM users WHERE name = $1`,
+	}, r.QueriesIssued())
+
+	r.AssertQueryTraced(t, "SELECT *\n\tFROM users\n\tWHERE name = $1")
+	r.AssertQueryCount(t, 2)
+
+	rm, err := r.Metrics(ctx)
+	require.NoError(t, err)
+
+	var calls int64
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "db.sql.client.calls" {
+				for _, dp := range sum.DataPoints {
+					calls += dp.Value
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, int64(2), calls)
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	t.Parallel()
+
+	dsn := newMock(t, func(m sqlmock.Sqlmock) {
+		m.ExpectExec(`DELETE FROM users`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	})
+
+	r := otelsqltest.New(t)
+
+	db, err := r.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	_, err = db.ExecContext(context.Background(), `DELETE FROM users`)
+	require.NoError(t, err)
+
+	r.Reset()
+
+	r.AssertNoQueries(t)
+	assert.Empty(t, r.Spans())
+}
+
+func TestRecorder_Open_DoesNotRegister(t *testing.T) {
+	t.Parallel()
+
+	dsn := newMock(t, func(m sqlmock.Sqlmock) {
+		m.ExpectExec(`DELETE FROM users`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	})
+
+	drivers := len(sql.Drivers())
+
+	// Every recorder has its own providers, the databases must not be registered as new drivers.
+	for i := 0; i < 3; i++ {
+		r := otelsqltest.New(t)
+
+		db, err := r.Open("sqlmock", dsn)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+	}
+
+	assert.Len(t, sql.Drivers(), drivers)
+
+	r := otelsqltest.New(t, otelsqltest.ExpectQueryCount(1))
+
+	db, err := r.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	_, err = db.ExecContext(context.Background(), `DELETE FROM users`)
+	require.NoError(t, err)
+}
+
+func TestRecorder_ErrSkip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		options  []otelsql.DriverOption
+	}{
+		{
+			scenario: "error",
+		},
+		{
+			scenario: "disable err skip",
+			options:  []otelsql.DriverOption{otelsql.DisableErrSkip()},
+		},
+		{
+			scenario: "ignore err skip",
+			options:  []otelsql.DriverOption{otelsql.WithErrorPolicy(otelsql.IgnoreErrSkip)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// database/sql executes the skipped exec again with a prepared statement.
+			dsn := newMock(t, func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`DELETE FROM users`).
+					WillReturnError(driver.ErrSkip)
+
+				m.ExpectPrepare(`DELETE FROM users`).
+					ExpectExec().
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			})
+
+			r := otelsqltest.New(t, otelsqltest.WithDriverOptions(tc.options...))
+
+			db, err := r.Open("sqlmock", dsn)
+			require.NoError(t, err)
+
+			defer db.Close() // nolint: errcheck
+
+			_, err = db.ExecContext(context.Background(), `DELETE FROM users`)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{`DELETE FROM users`}, r.QueriesIssued())
+		})
+	}
+}
+
+func TestRecorder_ExecThenPrepare(t *testing.T) {
+	t.Parallel()
+
+	// The exec is issued, the prepare of the same query that follows it is not a retry.
+	dsn := newMock(t, func(m sqlmock.Sqlmock) {
+		m.ExpectExec(`DELETE FROM users`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		m.ExpectPrepare(`DELETE FROM users`).
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	})
+
+	r := otelsqltest.New(t,
+		otelsqltest.ExpectQueryCount(2),
+		otelsqltest.WithDriverOptions(otelsql.DisableErrSkip()),
+	)
+
+	db, err := r.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `DELETE FROM users`)
+	require.NoError(t, err)
+
+	stmt, err := db.PrepareContext(ctx, `DELETE FROM users`)
+	require.NoError(t, err)
+
+	defer stmt.Close() // nolint: errcheck
+
+	_, err = stmt.ExecContext(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`DELETE FROM users`, `DELETE FROM users`}, r.QueriesIssued())
+}
+
+func TestRecorder_Failures(t *testing.T) {
+	t.Parallel()
+
+	dsn := newMock(t, func(m sqlmock.Sqlmock) {
+		m.ExpectExec(`DELETE FROM users`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	})
+
+	tb := &fakeTB{TB: t}
+
+	// The expectations of the recorder are checked before this cleanup.
+	t.Cleanup(func() {
+		require.Len(t, tb.errors, 4)
+		assert.Contains(t, tb.errors[3], "expected 3 queries, issued 1 queries")
+	})
+
+	r := otelsqltest.New(tb, otelsqltest.ExpectQueryCount(3))
+
+	db, err := r.Open("sqlmock", dsn)
+	require.NoError(t, err)
+
+	defer db.Close() // nolint: errcheck
+
+	_, err = db.ExecContext(context.Background(), `DELETE FROM users`)
+	require.NoError(t, err)
+
+	assert.False(t, r.AssertNoQueries(tb))
+	assert.False(t, r.AssertQueryTraced(tb, `SELECT * FROM users`))
+	assert.False(t, r.AssertQueryCount(tb, 2))
+
+	assert.Equal(t, []string{
+		`expected no queries, issued queries: ["DELETE FROM users"]`,
+		`query "SELECT * FROM users" has not been traced, issued queries: ["DELETE FROM users"]`,
+		`expected 2 queries, issued 1 queries: ["DELETE FROM users"]`,
+	}, tb.errors)
+}
@@ -15,9 +15,9 @@ type pingFuncMiddleware = middleware[pingFunc]
 // pingFunc is a callback for pingFunc.
 type pingFunc func(ctx context.Context) error
 
-// nopPing pings nothing.
-func nopPing(_ context.Context) error {
-	return nil
+// Ping satisfies driver.Pinger.
+func (f pingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
 }
 
 // pingStats records ping stats.
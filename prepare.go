@@ -3,6 +3,8 @@ package otelsql
 import (
 	"context"
 	"database/sql/driver"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -29,6 +31,18 @@ func ensurePrepareContext(conn driver.Conn) prepareContextFunc {
 	}
 }
 
+// prepareQueryInfo attaches the query and the id of the statement to prepare to the context.
+func prepareQueryInfo() prepareContextFuncMiddleware {
+	return func(next prepareContextFunc) prepareContextFunc {
+		return func(ctx context.Context, query string) (driver.Stmt, error) {
+			return next(ContextWithQueryInfo(ctx, QueryInfo{
+				Query:       query,
+				StatementID: stmtSequence.Add(1),
+			}), query)
+		}
+	}
+}
+
 // prepareStats records metrics for prepare.
 func prepareStats(r methodRecorder) prepareContextFuncMiddleware {
 	return func(next prepareContextFunc) prepareContextFunc {
@@ -48,59 +62,92 @@ func prepareStats(r methodRecorder) prepareContextFuncMiddleware {
 func prepareTrace(t methodTracer, traceQuery queryTracer) prepareContextFuncMiddleware {
 	return func(next prepareContextFunc) prepareContextFunc {
 		return func(ctx context.Context, query string) (stmt driver.Stmt, err error) {
-			ctx, end := t.Trace(ctx, traceMethodPrepare)
+			shouldTrace, hasParentSpan := t.ShouldTrace(ctx)
+			if !shouldTrace {
+				return next(ctx, query)
+			}
+
+			spanCtx, end := t.MustTrace(ctx, traceMethodPrepare)
+
+			if hasParentSpan {
+				ctx = contextWithOperationOf(ctx, spanCtx)
+			} else {
+				ctx = spanCtx
+			}
 
 			defer func() {
 				end(err, traceQuery(ctx, query, nil)...)
 			}()
 
-			return next(ctx, query)
+			// The executions of the statement are linked to the prepare span.
+			info, _ := QueryInfoFromContext(ctx)
+			info.PrepareSpan = trace.SpanContextFromContext(spanCtx)
+
+			return next(ContextWithQueryInfo(ctx, info), query)
 		}
 	}
 }
 
+// prepareWrapResult wraps the prepared statement and records the prepare call and the open statement.
 func prepareWrapResult(
+	r stmtRecorder,
 	execFuncMiddlewares []execContextFuncMiddleware,
 	execContextFuncMiddlewares []execContextFuncMiddleware,
 	queryFuncMiddlewares []queryContextFuncMiddleware,
 	queryContextFuncMiddlewares []queryContextFuncMiddleware,
+	closeFuncMiddlewares []closeFuncMiddleware,
 ) prepareContextFuncMiddleware {
 	return func(next prepareContextFunc) prepareContextFunc {
 		return func(ctx context.Context, query string) (driver.Stmt, error) {
+			r.RecordPrepare(ctx, query)
+
 			stmt, err := next(ctx, query)
 			if err != nil {
 				return nil, err
 			}
 
+			r.RecordOpen(ctx)
+
+			info, _ := QueryInfoFromContext(ctx)
+
 			return wrapStmt(stmt, stmtConfig{
 				query:                       query,
+				conn:                        connStateFromContext(ctx),
+				state:                       newStmtState(info),
 				execFuncMiddlewares:         execFuncMiddlewares,
 				queryContextFuncMiddlewares: queryContextFuncMiddlewares,
 				execContextFuncMiddlewares:  execContextFuncMiddlewares,
 				queryFuncMiddlewares:        queryFuncMiddlewares,
+				closeFuncMiddlewares:        closeFuncMiddlewares,
 			}), nil
 		}
 	}
 }
 
 type prepareConfig struct {
-	traceQuery queryTracer
+	traceQuery   queryTracer
+	stmtRecorder stmtRecorder
 
 	execFuncMiddlewares         []execContextFuncMiddleware
 	execContextFuncMiddlewares  []execContextFuncMiddleware
 	queryFuncMiddlewares        []queryContextFuncMiddleware
 	queryContextFuncMiddlewares []queryContextFuncMiddleware
+	closeFuncMiddlewares        []closeFuncMiddleware
 }
 
 func makePrepareContextFuncMiddlewares(r methodRecorder, t methodTracer, cfg prepareConfig) []prepareContextFuncMiddleware {
 	return []prepareContextFuncMiddleware{
+		prepareQueryInfo(),
 		prepareStats(r),
+		prepareQueryBudget(),
 		prepareTrace(t, cfg.traceQuery),
 		prepareWrapResult(
+			cfg.stmtRecorder,
 			cfg.execFuncMiddlewares,
 			cfg.execContextFuncMiddlewares,
 			cfg.queryFuncMiddlewares,
 			cfg.queryContextFuncMiddlewares,
+			cfg.closeFuncMiddlewares,
 		),
 	}
 }
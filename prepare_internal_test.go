@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 
@@ -23,7 +25,7 @@ func BenchmarkPrepareStats(b *testing.B) {
 	count, err := meter.Int64Counter("calls")
 	require.NoError(b, err)
 
-	r := newMethodRecorder(histogram.Record, count.Add,
+	r := newMethodRecorder(histogram.Record, count.Add, nil,
 		semconv.DBSystemOtherSQL,
 		dbInstance.String("test"),
 	)
@@ -192,7 +194,7 @@ func TestPrepareStats(t *testing.T) {
 					count, err := meter.Int64Counter(dbSQLClientCalls)
 					require.NoError(t, err)
 
-					r := newMethodRecorder(histogram.Record, count.Add,
+					r := newMethodRecorder(histogram.Record, count.Add, nil,
 						semconv.DBSystemOtherSQL,
 						dbInstance.String("test"),
 					)
@@ -207,6 +209,70 @@ func TestPrepareStats(t *testing.T) {
 	}
 }
 
+func TestPrepareWrapResult_RecordStmt(t *testing.T) {
+	t.Parallel()
+
+	r := &stmtRecorderFunc{}
+
+	prepare := chainMiddlewares([]prepareContextFuncMiddleware{
+		prepareWrapResult(r, nil, nil, nil, nil, nil),
+	}, func(_ context.Context, query string) (driver.Stmt, error) {
+		if query == "" {
+			return nil, errors.New("prepare error")
+		}
+
+		return struct{ driver.Stmt }{}, nil
+	})
+
+	_, err := prepare(context.Background(), "")
+	require.EqualError(t, err, "prepare error")
+
+	stmt, err := prepare(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+
+	assert.NotNil(t, stmt)
+	assert.Equal(t, []string{"", "SELECT 1"}, r.prepares)
+	assert.Equal(t, 1, r.opened)
+}
+
+func TestStmtRecorder_RecordPrepare(t *testing.T) {
+	t.Parallel()
+
+	const query = `SELECT * FROM orders WHERE user_id = 42`
+
+	testCases := []struct {
+		scenario    string
+		fingerprint bool
+		expected    attribute.Set
+	}{
+		{
+			scenario: "without fingerprint",
+			expected: attribute.NewSet(semconv.DBSystemPostgreSQL),
+		},
+		{
+			scenario:    "with fingerprint",
+			fingerprint: true,
+			expected:    attribute.NewSet(semconv.DBSystemPostgreSQL, dbSQLQueryFingerprint.String(queryFingerprint(query))),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []attribute.Set
+
+			r := newStmtRecorder(func(_ context.Context, _ int64, opts ...metric.AddOption) {
+				actual = append(actual, metric.NewAddConfig(opts).Attributes())
+			}, nil, nil, nil, tc.fingerprint, semconv.DBSystemPostgreSQL)
+
+			r.RecordPrepare(context.Background(), query)
+
+			assert.Equal(t, []attribute.Set{tc.expected}, actual)
+		})
+	}
+}
+
 type prepareTxTest struct {
 	driver.Conn
 	driver.ConnPrepareContext
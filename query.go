@@ -14,16 +14,16 @@ type queryContextFuncMiddleware = middleware[queryContextFunc]
 
 type queryContextFunc func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error)
 
+// QueryContext satisfies driver.QueryerContext.
+func (f queryContextFunc) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return f(ctx, query, args)
+}
+
 // nopQueryContext queries nothing.
 func nopQueryContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
 	return nil, nil //nolint: nilnil
 }
 
-// skippedQueryContext always returns driver.ErrSkip.
-func skippedQueryContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
-	return nil, driver.ErrSkip
-}
-
 // queryStats records metrics for query.
 func queryStats(r methodRecorder, method string) queryContextFuncMiddleware {
 	return func(next queryContextFunc) queryContextFunc {
@@ -42,14 +42,22 @@ func queryStats(r methodRecorder, method string) queryContextFuncMiddleware {
 }
 
 // queryTrace creates a span for query.
-func queryTrace(t methodTracer, traceQuery queryTracer, method string) queryContextFuncMiddleware {
+func queryTrace(t methodTracer, traceQuery queryTracer, method string, endAtRowsClose bool) queryContextFuncMiddleware {
 	return func(next queryContextFunc) queryContextFunc {
 		return func(ctx context.Context, query string, args []driver.NamedValue) (result driver.Rows, err error) {
-			ctx = ContextWithQuery(ctx, query)
+			ctx = contextWithQueryArgs(contextWithQuery(ctx, query), args)
 			ctx, end := t.Trace(ctx, method)
 
 			defer func() {
-				end(err, traceQuery(ctx, query, args)...)
+				if h := rowsHooksFromContext(ctx); endAtRowsClose && h != nil && err == nil {
+					h.end = func() {
+						end(h.err, append(traceQuery(ctx, query, args), h.spanAttributes()...)...)
+					}
+
+					return
+				}
+
+				end(err, append(traceQuery(ctx, query, args), skippedAttributes(err)...)...)
 			}()
 
 			result, err = next(ctx, query, args)
@@ -75,15 +83,32 @@ func queryWrapRows(t methodTracer, traceLastInsertID bool, traceRowsAffected boo
 }
 
 func makeQueryerContextMiddlewares(r methodRecorder, t methodTracer, cfg queryConfig) []queryContextFuncMiddleware {
-	middlewares := make([]queryContextFuncMiddleware, 0, 3)
+	middlewares := make([]queryContextFuncMiddleware, 0, 9)
+
+	middlewares = append(middlewares, queryStats(r, cfg.metricMethod), queryQueryBudget())
+
+	if cfg.nPlusOne != nil {
+		middlewares = append(middlewares, queryDetectNPlusOne(cfg.nPlusOne))
+	}
 
-	middlewares = append(middlewares, queryStats(r, cfg.metricMethod))
+	// The rows are only wrapped when they are summarized, the other queries do not pay for it.
+	if cfg.rowsRecorder != nil && cfg.traceUntilRowsClose {
+		middlewares = append(middlewares, queryRowsNextSummary(cfg.rowsRecorder, cfg.metricMethod, t != nil && cfg.traceRowsNextSummary))
+
+		if cfg.countRows {
+			middlewares = append(middlewares, queryCountRows(cfg.rowsRecorder, cfg.metricMethod))
+		}
+	}
 
 	if t == nil {
 		return middlewares
 	}
 
-	middlewares = append(middlewares, queryTrace(t, cfg.traceQuery, cfg.traceMethod))
+	middlewares = append(middlewares, queryTrace(t, cfg.traceQuery, cfg.traceMethod, cfg.traceUntilRowsClose))
+
+	if cfg.traceResultSets {
+		middlewares = append(middlewares, queryTraceResultSets(t))
+	}
 
 	if cfg.traceRowsNext || cfg.traceRowsClose {
 		middlewares = append(middlewares, queryWrapRows(t, cfg.traceRowsNext, cfg.traceRowsClose))
@@ -98,14 +123,36 @@ type queryConfig struct {
 	traceQuery     queryTracer
 	traceRowsNext  bool
 	traceRowsClose bool
+	// traceResultSets creates a span per result set.
+	traceResultSets bool
+	// traceRowsNextSummary adds the summary of the rows iteration to the query span.
+	traceRowsNextSummary bool
+	// traceUntilRowsClose summarizes the rows, records the response time and ends the query span when the rows are
+	// closed.
+	traceUntilRowsClose bool
+	// countRows counts the returned rows.
+	countRows    bool
+	rowsRecorder rowsRecorder
+	// nPlusOne detects the N+1 queries, if not nil.
+	nPlusOne *nPlusOneDetector
 }
 
-func newQueryConfig(opts driverOptions, metricMethod, traceMethod string) queryConfig {
-	return queryConfig{
+func newQueryConfig(opts driverOptions, rowsRecorder rowsRecorder, nPlusOne *nPlusOneDetector, metricMethod, traceMethod string) queryConfig {
+	cfg := queryConfig{
 		metricMethod:   metricMethod,
 		traceMethod:    traceMethod,
 		traceQuery:     opts.trace.queryTracer,
 		traceRowsNext:  opts.trace.RowsNext,
 		traceRowsClose: opts.trace.RowsClose,
+
+		traceResultSets: opts.trace.RowsResultSets,
+
+		traceRowsNextSummary: opts.trace.RowsNextSummary,
+		traceUntilRowsClose:  opts.trace.QueryUntilRowsClose || opts.trace.RowsNextSummary || opts.countRows || opts.recordResponseTime,
+		countRows:            opts.countRows,
+		rowsRecorder:         rowsRecorder,
+		nPlusOne:             nPlusOne,
 	}
+
+	return cfg
 }
@@ -23,7 +23,7 @@ func BenchmarkQueryStats(b *testing.B) {
 	count, err := meter.Int64Counter("calls")
 	require.NoError(b, err)
 
-	r := newMethodRecorder(histogram.Record, count.Add,
+	r := newMethodRecorder(histogram.Record, count.Add, nil,
 		semconv.DBSystemOtherSQL,
 		dbInstance.String("test"),
 	)
@@ -49,15 +49,6 @@ func TestNopQueryContext(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestSkippedQueryContext(t *testing.T) {
-	t.Parallel()
-
-	result, err := skippedQueryContext(context.Background(), "", nil)
-
-	assert.Nil(t, result)
-	assert.ErrorIs(t, err, driver.ErrSkip)
-}
-
 func TestChainQueryContextFuncMiddlewares_NoMiddleware(t *testing.T) {
 	t.Parallel()
 
@@ -166,7 +157,7 @@ func TestQueryStats(t *testing.T) {
 					count, err := meter.Int64Counter(dbSQLClientCalls)
 					require.NoError(t, err)
 
-					r := newMethodRecorder(histogram.Record, count.Add,
+					r := newMethodRecorder(histogram.Record, count.Add, nil,
 						semconv.DBSystemOtherSQL,
 						dbInstance.String("test"),
 					)
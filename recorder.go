@@ -12,6 +12,9 @@ import (
 // float64Recorder adds a new value to the list of Histogram's records.
 type float64Recorder = func(ctx context.Context, value float64, opts ...metric.RecordOption)
 
+// int64Recorder adds a new value to the list of Histogram's records.
+type int64Recorder = func(ctx context.Context, value int64, opts ...metric.RecordOption)
+
 // int64Counter adds the value to the counter's sum.
 type int64Counter = func(ctx context.Context, value int64, opts ...metric.AddOption)
 
@@ -23,6 +26,7 @@ type methodRecorder interface {
 type methodRecorderImpl struct {
 	recordLatency float64Recorder
 	countCalls    int64Counter
+	errorPolicies errorPolicies
 
 	attributes []attribute.KeyValue
 }
@@ -39,9 +43,14 @@ func (r methodRecorderImpl) Record(ctx context.Context, method string, labels ..
 	return func(err error) {
 		elapsedTime := millisecondsSince(startTime)
 
-		if err == nil {
+		switch r.errorPolicies.status(err) {
+		case ErrorStatusOK:
 			attrs = append(attrs, dbSQLStatusOK)
-		} else {
+
+		case ErrorStatusCanceled:
+			attrs = append(attrs, dbSQLStatusCANCELED)
+
+		default:
 			attrs = append(attrs, dbSQLStatusERROR,
 				dbSQLError.String(err.Error()),
 			)
@@ -56,11 +65,195 @@ func (r methodRecorderImpl) Record(ctx context.Context, method string, labels ..
 func newMethodRecorder(
 	latencyRecorder float64Recorder,
 	callsCounter int64Counter,
+	policies errorPolicies,
 	attrs ...attribute.KeyValue,
 ) methodRecorderImpl {
 	return methodRecorderImpl{
 		recordLatency: latencyRecorder,
 		countCalls:    callsCounter,
+		errorPolicies: policies,
 		attributes:    attrs,
 	}
 }
+
+// rowsRecorder records metrics about the rows returned by a query.
+type rowsRecorder interface {
+	RecordRows(ctx context.Context, method string, rows, bytes int64)
+	RecordRowsAffected(ctx context.Context, method string, rows int64)
+	RecordTimeToFirstRow(ctx context.Context, method string, d time.Duration)
+	RecordResponseDuration(ctx context.Context, method string, d time.Duration)
+}
+
+type rowsRecorderImpl struct {
+	recordRows             int64Recorder
+	recordBytes            int64Recorder
+	recordRowsAffected     int64Recorder
+	recordTimeToFirstRow   float64Recorder
+	recordResponseDuration float64Recorder
+
+	attributes []attribute.KeyValue
+}
+
+func (r rowsRecorderImpl) RecordRows(ctx context.Context, method string, rows, bytes int64) {
+	set := metric.WithAttributeSet(r.attributesSet(method))
+
+	r.recordRows(ctx, rows, set)
+	r.recordBytes(ctx, bytes, set)
+}
+
+func (r rowsRecorderImpl) RecordRowsAffected(ctx context.Context, method string, rows int64) {
+	r.recordRowsAffected(ctx, rows, metric.WithAttributeSet(r.attributesSet(method)))
+}
+
+func (r rowsRecorderImpl) RecordTimeToFirstRow(ctx context.Context, method string, d time.Duration) {
+	r.recordTimeToFirstRow(ctx, d.Seconds(), metric.WithAttributeSet(r.attributesSet(method)))
+}
+
+func (r rowsRecorderImpl) RecordResponseDuration(ctx context.Context, method string, d time.Duration) {
+	r.recordResponseDuration(ctx, d.Seconds(), metric.WithAttributeSet(r.attributesSet(method)))
+}
+
+func (r rowsRecorderImpl) attributesSet(method string) attribute.Set {
+	attrs := make([]attribute.KeyValue, 0, len(r.attributes)+1)
+
+	attrs = append(attrs, r.attributes...)
+	attrs = append(attrs, semconv.DBOperationKey.String(method))
+
+	return attribute.NewSet(attrs...)
+}
+
+func newRowsRecorder(
+	rowsRecorder, bytesRecorder, rowsAffectedRecorder int64Recorder,
+	timeToFirstRowRecorder, responseDurationRecorder float64Recorder,
+	attrs ...attribute.KeyValue,
+) rowsRecorderImpl {
+	return rowsRecorderImpl{
+		recordRows:             rowsRecorder,
+		recordBytes:            bytesRecorder,
+		recordRowsAffected:     rowsAffectedRecorder,
+		recordTimeToFirstRow:   timeToFirstRowRecorder,
+		recordResponseDuration: responseDurationRecorder,
+		attributes:             attrs,
+	}
+}
+
+// connRecorder records metrics about the lifecycle of a connection.
+type connRecorder interface {
+	RecordClose(ctx context.Context, s *connState)
+	RecordDiscard(ctx context.Context, reason string)
+	RecordWaitTime(ctx context.Context, waitTime time.Duration)
+	RecordCreateTime(ctx context.Context, createTime time.Duration)
+}
+
+type connRecorderImpl struct {
+	recordLifetime   float64Recorder
+	recordUses       int64Recorder
+	recordWaitTime   float64Recorder
+	recordCreateTime float64Recorder
+	countDiscards    int64Counter
+
+	attributes    []attribute.KeyValue
+	attributesSet attribute.Set
+}
+
+func (r connRecorderImpl) RecordClose(ctx context.Context, s *connState) {
+	r.recordLifetime(ctx, time.Since(s.createdAt).Seconds(), metric.WithAttributeSet(r.attributesSet))
+	r.recordUses(ctx, s.uses.Load(), metric.WithAttributeSet(r.attributesSet))
+}
+
+func (r connRecorderImpl) RecordDiscard(ctx context.Context, reason string) {
+	attrs := make([]attribute.KeyValue, 0, len(r.attributes)+1)
+
+	attrs = append(attrs, r.attributes...)
+	attrs = append(attrs, dbClientConnectionDiscardReason.String(reason))
+
+	r.countDiscards(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
+
+func (r connRecorderImpl) RecordWaitTime(ctx context.Context, waitTime time.Duration) {
+	r.recordWaitTime(ctx, waitTime.Seconds(), metric.WithAttributeSet(r.attributesSet))
+}
+
+func (r connRecorderImpl) RecordCreateTime(ctx context.Context, createTime time.Duration) {
+	r.recordCreateTime(ctx, createTime.Seconds(), metric.WithAttributeSet(r.attributesSet))
+}
+
+func newConnRecorder(
+	lifetimeRecorder float64Recorder,
+	usesRecorder int64Recorder,
+	waitTimeRecorder float64Recorder,
+	createTimeRecorder float64Recorder,
+	discardsCounter int64Counter,
+	attrs ...attribute.KeyValue,
+) connRecorderImpl {
+	return connRecorderImpl{
+		recordLifetime:   lifetimeRecorder,
+		recordUses:       usesRecorder,
+		recordWaitTime:   waitTimeRecorder,
+		recordCreateTime: createTimeRecorder,
+		countDiscards:    discardsCounter,
+		attributes:       attrs,
+		attributesSet:    attribute.NewSet(attrs...),
+	}
+}
+
+// stmtRecorder records metrics about the lifecycle of a prepared statement.
+type stmtRecorder interface {
+	RecordPrepare(ctx context.Context, query string)
+	RecordOpen(ctx context.Context)
+	RecordClose(ctx context.Context, s *stmtState)
+}
+
+type stmtRecorderImpl struct {
+	countPrepares    int64Counter
+	countOpen        int64Counter
+	recordLifetime   float64Recorder
+	recordExecutions int64Recorder
+	fingerprint      bool
+
+	attributes    []attribute.KeyValue
+	attributesSet attribute.Set
+}
+
+func (r stmtRecorderImpl) RecordPrepare(ctx context.Context, query string) {
+	if !r.fingerprint {
+		r.countPrepares(ctx, 1, metric.WithAttributeSet(r.attributesSet))
+
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(r.attributes)+1)
+
+	attrs = append(attrs, r.attributes...)
+	attrs = append(attrs, dbSQLQueryFingerprint.String(queryFingerprint(query)))
+
+	r.countPrepares(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
+
+func (r stmtRecorderImpl) RecordOpen(ctx context.Context) {
+	r.countOpen(ctx, 1, metric.WithAttributeSet(r.attributesSet))
+}
+
+func (r stmtRecorderImpl) RecordClose(ctx context.Context, s *stmtState) {
+	r.countOpen(ctx, -1, metric.WithAttributeSet(r.attributesSet))
+	r.recordLifetime(ctx, time.Since(s.preparedAt).Seconds(), metric.WithAttributeSet(r.attributesSet))
+	r.recordExecutions(ctx, s.uses.Load(), metric.WithAttributeSet(r.attributesSet))
+}
+
+func newStmtRecorder(
+	preparesCounter, openCounter int64Counter,
+	lifetimeRecorder float64Recorder,
+	executionsRecorder int64Recorder,
+	fingerprint bool,
+	attrs ...attribute.KeyValue,
+) stmtRecorderImpl {
+	return stmtRecorderImpl{
+		countPrepares:    preparesCounter,
+		countOpen:        openCounter,
+		recordLifetime:   lifetimeRecorder,
+		recordExecutions: executionsRecorder,
+		fingerprint:      fingerprint,
+		attributes:       attrs,
+		attributesSet:    attribute.NewSet(attrs...),
+	}
+}
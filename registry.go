@@ -0,0 +1,264 @@
+package otelsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const _maxDriver = 150
+
+var registry = struct {
+	sync.Mutex
+
+	byName map[string]*Registration
+	byKey  map[registryKey]*Registration
+}{
+	byName: make(map[string]*Registration),
+	byKey:  make(map[registryKey]*Registration),
+}
+
+// registryKey identifies a registration by the name of the parent driver and the fingerprint of the options.
+type registryKey struct {
+	parentName  string
+	fingerprint string
+}
+
+// Registration is a driver registered by Register, RegisterWithSource or MustRegister.
+type Registration struct {
+	// Name is the name of the otelsql driver, to be used with sql.Open.
+	Name string
+	// ParentName is the name of the wrapped driver.
+	ParentName string
+	// Parent is the wrapped driver.
+	Parent driver.Driver
+
+	options []DriverOption
+	key     registryKey
+}
+
+// Options returns the options that were used to register the driver.
+func (r Registration) Options() []DriverOption {
+	return slices.Clone(r.options)
+}
+
+// Lookup returns the registration of an otelsql driver name.
+func Lookup(name string) (Registration, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	r, ok := registry.byName[name]
+	if !ok {
+		return Registration{}, false
+	}
+
+	return *r, true
+}
+
+// MustRegister is like Register but panics if the driver could not be registered.
+func MustRegister(driverName string, options ...DriverOption) string {
+	name, err := Register(driverName, options...)
+	if err != nil {
+		panic(err)
+	}
+
+	return name
+}
+
+func register(parentName string, parent driver.Driver, options []DriverOption) (string, error) {
+	o := newDriverOptions(options...)
+	fingerprint, ok := o.fingerprint()
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	key := registryKey{parentName: parentName, fingerprint: fingerprint}
+
+	if o.driverName != "" {
+		return registerName(o.driverName, key, ok, parent, o, options)
+	}
+
+	if r, found := registry.byKey[key]; ok && found {
+		return r.Name, nil
+	}
+
+	// Since we might want to register multiple otelsql drivers to have different options, but potentially the same
+	// underlying database driver, we cycle through to find available driver names.
+	prefix := parentName + "-otelsql-"
+
+	for i := int64(0); i < _maxDriver; i++ {
+		name := prefix + strconv.FormatInt(i, 10)
+
+		if !slices.Contains(sql.Drivers(), name) {
+			doRegister(name, key, ok, parent, o, options)
+
+			return name, nil
+		}
+	}
+
+	return "", errors.New("unable to register driver, all slots have been taken")
+}
+
+func registerName(name string, key registryKey, fingerprinted bool, parent driver.Driver, o driverOptions, options []DriverOption) (string, error) {
+	if r, found := registry.byName[name]; found {
+		if fingerprinted && r.key == key {
+			return name, nil
+		}
+
+		return "", fmt.Errorf("otelsql: driver %q is already registered with different options", name)
+	}
+
+	if slices.Contains(sql.Drivers(), name) {
+		return "", fmt.Errorf("otelsql: driver %q is already registered", name)
+	}
+
+	doRegister(name, key, fingerprinted, parent, o, options)
+
+	return name, nil
+}
+
+func doRegister(name string, key registryKey, fingerprinted bool, parent driver.Driver, o driverOptions, options []DriverOption) {
+	sql.Register(name, wrapDriver(parent, newConnConfig(o)))
+
+	r := &Registration{
+		Name:       name,
+		ParentName: key.parentName,
+		Parent:     parent,
+		options:    slices.Clone(options),
+		key:        key,
+	}
+
+	registry.byName[name] = r
+
+	if fingerprinted {
+		if _, found := registry.byKey[key]; !found {
+			registry.byKey[key] = r
+		}
+	}
+}
+
+// fingerprint returns a string that is identical for identical options. It returns false if the options contain values
+// that cannot be compared, such as closures, in which case the registration cannot be reused.
+func (o driverOptions) fingerprint() (string, bool) {
+	var sb strings.Builder
+
+	v := reflect.ValueOf(o)
+
+	for i := range v.NumField() {
+		switch v.Type().Field(i).Name {
+		case "driverName", "recordStats", "statsOptions":
+			// Those options do not change the wrapped driver, the stats are only recorded by OpenDB.
+			continue
+		}
+
+		sb.WriteString(v.Type().Field(i).Name)
+		sb.WriteByte('=')
+
+		if !writeFingerprint(&sb, v.Field(i)) {
+			return "", false
+		}
+
+		sb.WriteByte(';')
+	}
+
+	return sb.String(), true
+}
+
+// nolint: cyclop,exhaustive
+func writeFingerprint(sb *strings.Builder, v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64,
+		reflect.String:
+		fmt.Fprintf(sb, "%v", v)
+
+	case reflect.Func:
+		if v.IsNil() {
+			sb.WriteString("nil")
+
+			return true
+		}
+
+		// Closures cannot be compared, only the named functions are.
+		name := runtime.FuncForPC(v.Pointer()).Name()
+		if name == "" || isClosure(name) {
+			return false
+		}
+
+		sb.WriteString(name)
+
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		fmt.Fprintf(sb, "%s@%x", v.Type(), v.Pointer())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("nil")
+
+			return true
+		}
+
+		fmt.Fprintf(sb, "%s:", v.Elem().Type())
+
+		return writeFingerprint(sb, v.Elem())
+
+	case reflect.Struct:
+		sb.WriteByte('{')
+
+		for i := range v.NumField() {
+			if !writeFingerprint(sb, v.Field(i)) {
+				return false
+			}
+
+			sb.WriteByte(',')
+		}
+
+		sb.WriteByte('}')
+
+	case reflect.Slice, reflect.Array:
+		sb.WriteByte('[')
+
+		for i := range v.Len() {
+			if !writeFingerprint(sb, v.Index(i)) {
+				return false
+			}
+
+			sb.WriteByte(',')
+		}
+
+		sb.WriteByte(']')
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// isClosure checks whether the function name is generated by the compiler for a function literal or a method value, for
+// example "pkg.Func.func1", "pkg.Func.func1.2" or "pkg.T.Method-fm".
+func isClosure(name string) bool {
+	if strings.HasSuffix(name, "-fm") {
+		return true
+	}
+
+	name = name[strings.LastIndexByte(name, '/')+1:]
+
+	for _, part := range strings.Split(name, ".")[1:] {
+		if strings.HasPrefix(part, "func") || strings.HasPrefix(part, "gowrap") {
+			return true
+		}
+
+		if _, err := strconv.Atoi(part); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package otelsql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestIsClosure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "go.nhat.io/otelsql.formatSpanName"},
+		{name: "go.nhat.io/otelsql.(*methodTracerImpl).Trace"},
+		{name: "go.nhat.io/otelsql.TestIsClosure.func1", expected: true},
+		{name: "go.nhat.io/otelsql.TestIsClosure.func1.2", expected: true},
+		{name: "go.nhat.io/otelsql.(*methodTracerImpl).Trace-fm", expected: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, isClosure(tc.name))
+		})
+	}
+}
+
+func TestDriverOptions_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	meterProvider := noop.NewMeterProvider()
+
+	fingerprint := func(opts ...DriverOption) string {
+		t.Helper()
+
+		actual, ok := newDriverOptions(opts...).fingerprint()
+
+		assert.True(t, ok)
+
+		return actual
+	}
+
+	assert.Equal(t, fingerprint(), fingerprint())
+	assert.Equal(t, fingerprint(TraceAll(), WithInstanceName("a")), fingerprint(TraceAll(), WithInstanceName("a")))
+	assert.Equal(t, fingerprint(WithMeterProvider(meterProvider)), fingerprint(WithMeterProvider(meterProvider)))
+	assert.Equal(t, fingerprint(WithDriverName("a"), WithRecordStats()), fingerprint(WithDriverName("b")))
+
+	assert.NotEqual(t, fingerprint(), fingerprint(TracePing()))
+	assert.NotEqual(t, fingerprint(WithInstanceName("a")), fingerprint(WithInstanceName("b")))
+	assert.NotEqual(t, fingerprint(TraceQueryWithArgs()), fingerprint(TraceQueryWithoutArgs()))
+	assert.Equal(t, fingerprint(IgnoreUniqueViolations()), fingerprint(IgnoreUniqueViolations()))
+	assert.NotEqual(t, fingerprint(IgnoreUniqueViolations()), fingerprint(IgnoreSerializationFailures()))
+
+	_, ok := newDriverOptions(WithSpanNameFormatter(func(context.Context, string) string {
+		return ""
+	})).fingerprint()
+
+	assert.False(t, ok)
+}
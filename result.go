@@ -3,8 +3,12 @@ package otelsql
 import (
 	"context"
 	"database/sql/driver"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+
+	xattr "go.nhat.io/otelsql/attribute"
 )
 
 const (
@@ -17,6 +21,8 @@ type resultFunc func() (int64, error)
 var _ driver.Result = (*result)(nil)
 
 type result struct {
+	parent driver.Result
+
 	lastInsertIDFunc resultFunc
 	rowsAffectedFunc resultFunc
 }
@@ -29,24 +35,40 @@ func (r result) RowsAffected() (int64, error) {
 	return r.rowsAffectedFunc()
 }
 
-func wrapResult(ctx context.Context, parent driver.Result, t methodTracer, traceLastInsertID bool, traceRowsAffected bool) driver.Result {
+// Unwrap returns the underlying driver.Result.
+func (r result) Unwrap() driver.Result {
+	return r.parent
+}
+
+func wrapResult(ctx context.Context, parent driver.Result, t methodTracer, traceLastInsertID bool, traceRowsAffected bool, events bool) driver.Result {
 	if !traceLastInsertID && !traceRowsAffected {
 		return parent
 	}
 
-	ctx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	// The span is taken before detaching the context, which only keeps the span context.
+	span := trace.SpanFromContext(ctx)
+	ctx = detachContext(ctx)
 
 	r := &result{
+		parent:           parent,
 		lastInsertIDFunc: parent.LastInsertId,
 		rowsAffectedFunc: parent.RowsAffected,
 	}
 
 	if traceLastInsertID {
 		r.lastInsertIDFunc = resultTrace(ctx, t, traceMethodLastInsertID, parent.LastInsertId)
+
+		if events {
+			r.lastInsertIDFunc = resultEvent(span, traceMethodLastInsertID, dbSQLLastInsertID, parent.LastInsertId, r.lastInsertIDFunc)
+		}
 	}
 
 	if traceRowsAffected {
 		r.rowsAffectedFunc = resultTrace(ctx, t, traceMethodRowsAffected, parent.RowsAffected)
+
+		if events {
+			r.rowsAffectedFunc = resultEvent(span, traceMethodRowsAffected, dbSQLRowsAffected, parent.RowsAffected, r.rowsAffectedFunc)
+		}
 	}
 
 	return r
@@ -65,3 +87,30 @@ func resultTrace(ctx context.Context, t methodTracer, method string, f resultFun
 		return
 	}
 }
+
+// resultEvent adds an event with the result and the latency of the call to the span. The span is the exec span itself
+// when the exec has no parent span, and it has ended by then, so the call is traced by fallback instead.
+func resultEvent(span trace.Span, method string, key attribute.Key, f resultFunc, fallback resultFunc) resultFunc {
+	return func() (int64, error) {
+		if !span.IsRecording() {
+			return fallback()
+		}
+
+		startTime := time.Now()
+
+		result, err := f()
+
+		attrs := make([]attribute.KeyValue, 0, 2)
+		attrs = append(attrs, xattr.KeyValueDuration(dbSQLLatency, time.Since(startTime)))
+
+		if err != nil {
+			attrs = append(attrs, dbSQLError.String(err.Error()))
+		} else {
+			attrs = append(attrs, key.Int64(result))
+		}
+
+		span.AddEvent(method, trace.WithAttributes(attrs...))
+
+		return result, err
+	}
+}
@@ -0,0 +1,122 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const traceMethodResultSet = "result_set"
+
+var _ withRowsNextResultSet = (*rowsNextResultSet)(nil)
+
+type rowsNextResultSet struct {
+	hasNextResultSetFunc func() bool
+	nextResultSetFunc    func() error
+}
+
+func (r rowsNextResultSet) HasNextResultSet() bool {
+	return r.hasNextResultSetFunc()
+}
+
+func (r rowsNextResultSet) NextResultSet() error {
+	return r.nextResultSetFunc()
+}
+
+// resultSetTracer creates a span per result set of the rows, from the query or the switch to the result set until the
+// switch to the next one or the rows close. Most queries return a single result set, so no span is created until the
+// first successful switch, the span of the first result set is then created after the fact.
+type resultSetTracer struct {
+	ctx context.Context
+	t   methodTracer
+
+	index     int64
+	count     int64
+	startTime time.Time
+	end       func(err error, attrs ...attribute.KeyValue)
+}
+
+func (s *resultSetTracer) start() {
+	_, s.end = s.t.MustTrace(contextWithStartTime(s.ctx, s.startTime), traceMethodResultSet)
+}
+
+// next switches to the next result set.
+func (s *resultSetTracer) next(err error) {
+	switchTime := time.Now()
+
+	if s.end == nil && err == nil {
+		s.start()
+	}
+
+	s.finish(err)
+
+	if err == nil {
+		s.index++
+		s.count = 0
+		s.startTime = switchTime
+
+		s.start()
+	}
+}
+
+func (s *resultSetTracer) finish(err error) {
+	if s.end == nil {
+		return
+	}
+
+	s.end(err, dbSQLResultSetIndex.Int64(s.index), dbSQLResultSetRowsCount.Int64(s.count))
+	s.end = nil
+}
+
+// queryTraceResultSets creates a span per result set of the rows returned by query, if the driver supports multiple
+// result sets and the rows have more than one.
+func queryTraceResultSets(t methodTracer) queryContextFuncMiddleware {
+	return func(next queryContextFunc) queryContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+			ctx, h, attached := contextWithRowsHooks(ctx)
+
+			result, err := next(ctx, query, args)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := result.(withRowsNextResultSet); ok {
+				if shouldTrace, _ := t.ShouldTrace(ctx); shouldTrace {
+					traceResultSets(ctx, h, t)
+				}
+			}
+
+			return h.wrap(result, attached), nil
+		}
+	}
+}
+
+// traceResultSets adds the hooks that trace the result sets to the rows.
+func traceResultSets(ctx context.Context, h *rowsHooks, t methodTracer) {
+	s := &resultSetTracer{ctx: detachContext(ctx), t: t, startTime: time.Now()}
+
+	h.onNext = append(h.onNext, func(_ []driver.Value, err error) {
+		if err == nil {
+			s.count++
+		}
+	})
+
+	h.onNextResultSet = append(h.onNextResultSet, func(err error) {
+		// The drivers return io.EOF when there is no more result set.
+		if errors.Is(err, io.EOF) {
+			s.finish(nil)
+
+			return
+		}
+
+		s.next(err)
+	})
+
+	h.onClose = append(h.onClose, func() {
+		s.finish(nil)
+	})
+}
@@ -0,0 +1,145 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTraceResultSets(t *testing.T) {
+	t.Parallel()
+
+	switchError := errors.New("switch error")
+
+	testCases := []struct {
+		scenario       string
+		nextResultSets []error
+		expected       []tracedSpan
+	}{
+		{
+			scenario: "one result set",
+		},
+		{
+			scenario:       "many result sets",
+			nextResultSets: []error{nil, io.EOF},
+			expected: []tracedSpan{
+				{method: traceMethodResultSet, attrs: resultSetAttributes(0, 2)},
+				{method: traceMethodResultSet, attrs: resultSetAttributes(1, 2)},
+			},
+		},
+		{
+			scenario:       "first switch error",
+			nextResultSets: []error{switchError},
+		},
+		{
+			scenario:       "switch error",
+			nextResultSets: []error{nil, switchError},
+			expected: []tracedSpan{
+				{method: traceMethodResultSet, attrs: resultSetAttributes(0, 2)},
+				{method: traceMethodResultSet, err: switchError, attrs: resultSetAttributes(1, 2)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				tracer  = &spanRecorder{}
+				results = tc.nextResultSets
+				next    = 0
+			)
+
+			parent := struct {
+				rowsNextFunc
+				rowsColumnFunc
+				rowsCloseFunc
+				rowsHasNextResultSetFunc
+				rowsNextResultSetFunc
+			}{
+				rowsNextFunc: func([]driver.Value) error {
+					if next++; next%3 == 0 {
+						return io.EOF
+					}
+
+					return nil
+				},
+				rowsCloseFunc: func() error {
+					return nil
+				},
+				rowsHasNextResultSetFunc: func() bool {
+					return len(results) > 0
+				},
+				rowsNextResultSetFunc: func() error {
+					err := results[0]
+					results = results[1:]
+
+					return err
+				},
+			}
+
+			h := &rowsHooks{}
+
+			traceResultSets(context.Background(), h, tracer)
+
+			rows := h.wrap(parent, true)
+
+			nrs, ok := rows.(driver.RowsNextResultSet)
+			require.True(t, ok)
+
+			for {
+				for rows.Next(nil) == nil {
+				}
+
+				if !nrs.HasNextResultSet() || nrs.NextResultSet() != nil {
+					break
+				}
+			}
+
+			require.NoError(t, rows.Close())
+
+			assert.Equal(t, tc.expected, tracer.spans)
+		})
+	}
+}
+
+func resultSetAttributes(index, count int64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		dbSQLResultSetIndex.Int64(index),
+		dbSQLResultSetRowsCount.Int64(count),
+	}
+}
+
+type tracedSpan struct {
+	method string
+	err    error
+	attrs  []attribute.KeyValue
+}
+
+// spanRecorder is a methodTracer that records the ended spans.
+type spanRecorder struct {
+	spans []tracedSpan
+}
+
+func (r *spanRecorder) ShouldTrace(context.Context) (bool, bool) {
+	return true, true
+}
+
+func (r *spanRecorder) MustTrace(ctx context.Context, method string, _ ...attribute.KeyValue) (context.Context, func(err error, attrs ...attribute.KeyValue)) {
+	return ctx, func(err error, attrs ...attribute.KeyValue) {
+		r.spans = append(r.spans, tracedSpan{method: method, err: err, attrs: attrs})
+	}
+}
+
+func (r *spanRecorder) Trace(ctx context.Context, method string, labels ...attribute.KeyValue) (context.Context, func(err error, attrs ...attribute.KeyValue)) {
+	return r.MustTrace(ctx, method, labels...)
+}
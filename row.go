@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 
 	xattr "go.nhat.io/otelsql/attribute"
 )
@@ -21,53 +20,49 @@ const (
 
 var _ driver.Rows = (*rows)(nil)
 
-// withRowsColumnTypeScanType is the same as the driver.RowsColumnTypeScanType interface except it omits the driver.Rows embedded interface.
-// If the original driver.Rows implementation wrapped by ocsql supports RowsColumnTypeScanType we enable the original method implementation in the returned
-// driver.Rows from wrapRows by doing a composition with ocRows.
-type withRowsColumnTypeScanType interface {
-	ColumnTypeScanType(index int) reflect.Type
-}
-
-type rowsNextFunc func(dest []driver.Value) (err error)
-
-type rowsCloseFunc func() error
-
-type rowsColumnFunc func() []string
+// The following interfaces are the same as the optional driver.Rows interfaces except they omit the driver.Rows embedded
+// interface, so they can be composed with rows without ambiguous selectors.
 
-type rows struct {
-	hasNextResultSetFunc           func() bool
-	nextResultSetFunc              func() error
-	columnTypeDatabaseTypeNameFunc func(index int) string
-	columnTypeLengthFunc           func(index int) (length int64, ok bool)
-	columnTypeNullableFunc         func(index int) (nullable, ok bool)
-	columnTypePrecisionScaleFunc   func(index int) (precision, scale int64, ok bool)
-	columnsFunc                    rowsColumnFunc
-	closeFunc                      rowsCloseFunc
-	nextFunc                       rowsNextFunc
+type withRowsNextResultSet interface {
+	HasNextResultSet() bool
+	NextResultSet() error
 }
 
-func (r rows) HasNextResultSet() bool {
-	return r.hasNextResultSetFunc()
+type withRowsColumnTypeDatabaseTypeName interface {
+	ColumnTypeDatabaseTypeName(index int) string
 }
 
-func (r rows) NextResultSet() error {
-	return r.nextResultSetFunc()
+type withRowsColumnTypeLength interface {
+	ColumnTypeLength(index int) (length int64, ok bool)
 }
 
-func (r rows) ColumnTypeDatabaseTypeName(index int) string {
-	return r.columnTypeDatabaseTypeNameFunc(index)
+type withRowsColumnTypeNullable interface {
+	ColumnTypeNullable(index int) (nullable, ok bool)
 }
 
-func (r rows) ColumnTypeLength(index int) (length int64, ok bool) {
-	return r.columnTypeLengthFunc(index)
+type withRowsColumnTypePrecisionScale interface {
+	ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool)
 }
 
-func (r rows) ColumnTypeNullable(index int) (nullable, ok bool) {
-	return r.columnTypeNullableFunc(index)
+type withRowsColumnTypeScanType interface {
+	ColumnTypeScanType(index int) reflect.Type
 }
 
-func (r rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
-	return r.columnTypePrecisionScaleFunc(index)
+type rowsNextFunc func(dest []driver.Value) (err error)
+
+type rowsCloseFunc func() error
+
+type rowsColumnFunc func() []string
+
+type rows struct {
+	parent driver.Rows
+
+	columnsFunc rowsColumnFunc
+	closeFunc   rowsCloseFunc
+	nextFunc    rowsNextFunc
+
+	// nextResultSet, if not nil, replaces the driver.RowsNextResultSet methods of the parent.
+	nextResultSet withRowsNextResultSet
 }
 
 func (r rows) Columns() []string {
@@ -82,24 +77,23 @@ func (r rows) Next(dest []driver.Value) (err error) {
 	return r.nextFunc(dest)
 }
 
-// nolint: cyclop
+// Unwrap returns the underlying driver.Rows.
+func (r rows) Unwrap() driver.Rows {
+	return r.parent
+}
+
 func wrapRows(ctx context.Context, parent driver.Rows, t methodTracer, traceRowsNext bool, traceRowsClose bool) driver.Rows {
 	if !traceRowsNext && !traceRowsClose {
 		return parent
 	}
 
-	ctx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	ctx = detachContext(ctx)
 
 	r := rows{
-		columnsFunc:                    parent.Columns,
-		closeFunc:                      parent.Close,
-		nextFunc:                       parent.Next,
-		hasNextResultSetFunc:           func() bool { return false },
-		nextResultSetFunc:              func() error { return io.EOF },
-		columnTypeDatabaseTypeNameFunc: func(int) string { return "" },
-		columnTypeLengthFunc:           func(int) (int64, bool) { return 0, false },
-		columnTypeNullableFunc:         func(int) (bool, bool) { return false, false },
-		columnTypePrecisionScaleFunc:   func(int) (int64, int64, bool) { return 0, 0, false },
+		parent:      parent,
+		columnsFunc: parent.Columns,
+		closeFunc:   parent.Close,
+		nextFunc:    parent.Next,
 	}
 
 	if traceRowsClose {
@@ -111,35 +105,139 @@ func wrapRows(ctx context.Context, parent driver.Rows, t methodTracer, traceRows
 		r.nextFunc = rowsNextTrace(ctx, t, r.nextFunc)
 	}
 
-	if v, ok := parent.(driver.RowsNextResultSet); ok {
-		r.hasNextResultSetFunc = v.HasNextResultSet
-		r.nextResultSetFunc = v.NextResultSet
+	return combineRowsOf(r)
+}
+
+// combineRowsOf combines rows with the optional interfaces that its parent implements.
+func combineRowsOf(r rows) driver.Rows {
+	var (
+		nrs    = r.nextResultSet
+		dtn, _ = r.parent.(withRowsColumnTypeDatabaseTypeName) //nolint: errcheck
+		l, _   = r.parent.(withRowsColumnTypeLength)           //nolint: errcheck
+		n, _   = r.parent.(withRowsColumnTypeNullable)         //nolint: errcheck
+		ps, _  = r.parent.(withRowsColumnTypePrecisionScale)   //nolint: errcheck
+		st, _  = r.parent.(withRowsColumnTypeScanType)         //nolint: errcheck
+	)
+
+	if nrs == nil {
+		nrs, _ = r.parent.(withRowsNextResultSet) //nolint: errcheck
 	}
 
-	if v, ok := parent.(driver.RowsColumnTypeDatabaseTypeName); ok {
-		r.columnTypeDatabaseTypeNameFunc = v.ColumnTypeDatabaseTypeName
+	return combineRows(r, nrs, dtn, l, n, ps, st)
+}
+
+type rowsHooksCtxKey struct{}
+
+// rowsHooks are the single hook point of the rows returned by a query, so that the middlewares of the query can observe
+// the consumption of the rows without wrapping them one over another. The query span ends when the rows are closed,
+// with the attributes of the hooks.
+type rowsHooks struct {
+	// attributes are added to the query span.
+	attributes []func() []attribute.KeyValue
+	// onNext are called in order after each call to Next.
+	onNext []func(dest []driver.Value, err error)
+	// onNextResultSet are called in order after each call to NextResultSet.
+	onNextResultSet []func(err error)
+	// onClose are called in order after closing the rows.
+	onClose []func()
+	// end, if not nil, ends the query span after the other hooks.
+	end func()
+	// err is the first error returned by Next, other than io.EOF, or by Close.
+	err error
+}
+
+// fail keeps the first error of the rows, io.EOF only ends the iteration.
+func (h *rowsHooks) fail(err error) {
+	if h.err == nil && err != nil && !errors.Is(err, io.EOF) {
+		h.err = err
+	}
+}
+
+func (h *rowsHooks) spanAttributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	for _, f := range h.attributes {
+		attrs = append(attrs, f()...)
 	}
 
-	if v, ok := parent.(driver.RowsColumnTypeLength); ok {
-		r.columnTypeLengthFunc = v.ColumnTypeLength
+	return attrs
+}
+
+// wrap wraps the rows to run the hooks if the caller attached them, see contextWithRowsHooks, and if there are any.
+func (h *rowsHooks) wrap(parent driver.Rows, attached bool) driver.Rows {
+	if !attached || len(h.onNext) == 0 && len(h.onNextResultSet) == 0 && len(h.onClose) == 0 && h.end == nil {
+		return parent
+	}
+
+	r := rows{
+		parent:      parent,
+		columnsFunc: parent.Columns,
+		nextFunc: func(dest []driver.Value) error {
+			err := parent.Next(dest)
+
+			h.fail(err)
+
+			for _, f := range h.onNext {
+				f(dest, err)
+			}
+
+			return err
+		},
+		closeFunc: func() error {
+			err := parent.Close()
+
+			h.fail(err)
+
+			for _, f := range h.onClose {
+				f()
+			}
+
+			if h.end != nil {
+				h.end()
+			}
+
+			return err
+		},
 	}
 
-	if v, ok := parent.(driver.RowsColumnTypeNullable); ok {
-		r.columnTypeNullableFunc = v.ColumnTypeNullable
+	if nrs, ok := parent.(withRowsNextResultSet); ok && len(h.onNextResultSet) > 0 {
+		r.nextResultSet = rowsNextResultSet{
+			hasNextResultSetFunc: nrs.HasNextResultSet,
+			nextResultSetFunc: func() error {
+				err := nrs.NextResultSet()
+
+				for _, f := range h.onNextResultSet {
+					f(err)
+				}
+
+				return err
+			},
+		}
 	}
 
-	if v, ok := parent.(driver.RowsColumnTypePrecisionScale); ok {
-		r.columnTypePrecisionScaleFunc = v.ColumnTypePrecisionScale
+	return combineRowsOf(r)
+}
+
+// rowsHooksFromContext gets the rows hooks of the query from context.
+func rowsHooksFromContext(ctx context.Context) *rowsHooks {
+	h, ok := ctx.Value(rowsHooksCtxKey{}).(*rowsHooks)
+	if !ok {
+		return nil
 	}
 
-	if ts, ok := parent.(withRowsColumnTypeScanType); ok {
-		return struct {
-			rows
-			withRowsColumnTypeScanType
-		}{r, ts}
+	return h
+}
+
+// contextWithRowsHooks gets the rows hooks of the query from context, or attaches new ones if there are none yet. The
+// middleware that attaches them is the outermost one that observes the rows, so the rows are wrapped only once.
+func contextWithRowsHooks(ctx context.Context) (context.Context, *rowsHooks, bool) {
+	if h := rowsHooksFromContext(ctx); h != nil {
+		return ctx, h, false
 	}
 
-	return r
+	h := &rowsHooks{}
+
+	return context.WithValue(ctx, rowsHooksCtxKey{}, h), h, true
 }
 
 func rowsNextTrace(ctx context.Context, t methodTracer, f rowsNextFunc) rowsNextFunc {
@@ -47,14 +47,6 @@ func TestWrapRows_RowsNextResultSet(t *testing.T) {
 		expectedHasNextResultSet   bool
 		expectedNextResultSetError error
 	}{
-		{
-			scenario: "Rows",
-			parent: struct {
-				driver.Rows
-			}{},
-			expectedHasNextResultSet:   false,
-			expectedNextResultSetError: io.EOF,
-		},
 		{
 			scenario: "RowsNextResultSet",
 			parent: struct {
@@ -102,13 +94,6 @@ func TestWrapRows_RowsColumnTypeDatabaseTypeName(t *testing.T) {
 		parent   driver.Rows
 		expected string
 	}{
-		{
-			scenario: "Rows",
-			parent: struct {
-				driver.Rows
-			}{},
-			expected: "",
-		},
 		{
 			scenario: "RowsColumnTypeDatabaseTypeName",
 			parent: struct {
@@ -150,14 +135,6 @@ func TestWrapRows_RowsColumnTypeLength(t *testing.T) {
 		expectedLength int64
 		expectedOK     bool
 	}{
-		{
-			scenario: "Rows",
-			parent: struct {
-				driver.Rows
-			}{},
-			expectedLength: 0,
-			expectedOK:     false,
-		},
 		{
 			scenario: "RowsColumnTypeLength",
 			parent: struct {
@@ -201,14 +178,6 @@ func TestWrapRows_RowsColumnTypeNullable(t *testing.T) {
 		expectedNullable bool
 		expectedOK       bool
 	}{
-		{
-			scenario: "Rows",
-			parent: struct {
-				driver.Rows
-			}{},
-			expectedNullable: false,
-			expectedOK:       false,
-		},
 		{
 			scenario: "RowsColumnTypeNullable",
 			parent: struct {
@@ -253,15 +222,6 @@ func TestWrapRows_RowsColumnTypePrecisionScale(t *testing.T) {
 		expectedScale     int64
 		expectedOK        bool
 	}{
-		{
-			scenario: "Rows",
-			parent: struct {
-				driver.Rows
-			}{},
-			expectedPrecision: 0,
-			expectedScale:     0,
-			expectedOK:        false,
-		},
 		{
 			scenario: "RowsColumnTypePrecisionScale",
 			parent: struct {
@@ -385,6 +345,74 @@ func (f rowsColumnTypeLengthFunc) ColumnTypeLength(index int) (int64, bool) {
 	return f(index)
 }
 
+func TestRowsHooks_Fail(t *testing.T) {
+	t.Parallel()
+
+	h := &rowsHooks{}
+
+	h.fail(nil)
+	h.fail(io.EOF)
+
+	require.NoError(t, h.err)
+
+	h.fail(errors.New("next error"))
+	h.fail(errors.New("close error"))
+
+	assert.EqualError(t, h.err, "next error")
+}
+
+func TestRowsHooks_Wrap(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	parent := struct {
+		rowsNextFunc
+		rowsColumnFunc
+		rowsCloseFunc
+	}{
+		rowsNextFunc: func([]driver.Value) error {
+			return io.EOF
+		},
+		rowsCloseFunc: func() error {
+			return nil
+		},
+	}
+
+	h := &rowsHooks{}
+
+	isWrapped := func(r driver.Rows) bool {
+		_, ok := r.(interface{ Unwrap() driver.Rows })
+
+		return ok
+	}
+
+	// The rows are not wrapped without hooks, or by the middlewares that did not attach the hooks.
+	assert.False(t, isWrapped(h.wrap(parent, true)))
+
+	h.end = func() { calls = append(calls, "end") }
+	h.onClose = append(h.onClose,
+		func() { calls = append(calls, "close 1") },
+		func() { calls = append(calls, "close 2") },
+	)
+	h.onNext = append(h.onNext, func(_ []driver.Value, err error) {
+		calls = append(calls, "next")
+
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	assert.False(t, isWrapped(h.wrap(parent, false)))
+
+	r := h.wrap(parent, true)
+
+	assert.True(t, isWrapped(r))
+
+	require.ErrorIs(t, r.Next(nil), io.EOF)
+	require.NoError(t, r.Close())
+
+	assert.Equal(t, []string{"next", "close 1", "close 2", "end"}, calls)
+}
+
 type rowsColumnTypeNullableFunc func(index int) (nullable, ok bool)
 
 func (f rowsColumnTypeNullableFunc) ColumnTypeNullable(index int) (nullable, ok bool) {
@@ -0,0 +1,136 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type rowsAffectedMode int
+
+const (
+	rowsAffectedNone rowsAffectedMode = iota
+	// rowsAffectedEager calls RowsAffected as soon as exec returns.
+	rowsAffectedEager
+	// rowsAffectedLazy waits for the caller to call RowsAffected.
+	rowsAffectedLazy
+)
+
+type rowsAffectedCtxKey struct{}
+
+// rowsAffected reads the rows affected by an exec at most once.
+type rowsAffected struct {
+	count  int64
+	err    error
+	loaded bool
+}
+
+func (a *rowsAffected) load(result driver.Result) (int64, error) {
+	if !a.loaded {
+		a.count, a.err = result.RowsAffected()
+		a.loaded = true
+	}
+
+	return a.count, a.err
+}
+
+func (a *rowsAffected) attributes() []attribute.KeyValue {
+	if !a.loaded || a.err != nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{dbSQLRowsAffected.Int64(a.count)}
+}
+
+// rowsAffectedFromContext gets the rows affected by the exec from context.
+func rowsAffectedFromContext(ctx context.Context) *rowsAffected {
+	a, ok := ctx.Value(rowsAffectedCtxKey{}).(*rowsAffected)
+	if !ok {
+		return nil
+	}
+
+	return a
+}
+
+// execRecordRowsAffected records the rows affected by exec. In the eager mode, the rows affected are read when exec
+// returns, see execLoadRowsAffected, and the inner middlewares find them in the context. In the lazy mode, they are
+// recorded when the caller calls RowsAffected, if ever.
+//
+// The RowsAffected calls of the caller are always passed to the result of the inner middlewares, so that they are still
+// traced with TraceRowsAffected or TraceResultEvents.
+func execRecordRowsAffected(r rowsRecorder, method string, mode rowsAffectedMode) execContextFuncMiddleware {
+	return func(next execContextFunc) execContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			a := &rowsAffected{}
+
+			if mode == rowsAffectedEager {
+				ctx = context.WithValue(ctx, rowsAffectedCtxKey{}, a)
+			}
+
+			res, err := next(ctx, query, args)
+			if err != nil {
+				return nil, err
+			}
+
+			// Some drivers do not return a result, there is nothing to record.
+			if res == nil {
+				return res, nil
+			}
+
+			ctx = detachContext(ctx)
+
+			var recorded bool
+
+			record := func(n int64, err error) {
+				if err == nil && !recorded {
+					recorded = true
+
+					r.RecordRowsAffected(ctx, method, n)
+				}
+			}
+
+			if mode == rowsAffectedEager {
+				record(a.load(res))
+			}
+
+			return &result{
+				parent:           res,
+				lastInsertIDFunc: res.LastInsertId,
+				rowsAffectedFunc: func() (int64, error) {
+					n, err := res.RowsAffected()
+
+					record(n, err)
+
+					return n, err
+				},
+			}, nil
+		}
+	}
+}
+
+// execLoadRowsAffected reads the rows affected from the result of the driver in the eager mode. It is the innermost
+// middleware, so that the read is not traced as a RowsAffected call of the caller. The RowsAffected calls of the caller
+// get the rows affected that are already read, so the driver is called only once.
+func execLoadRowsAffected() execContextFuncMiddleware {
+	return func(next execContextFunc) execContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			res, err := next(ctx, query, args)
+
+			a := rowsAffectedFromContext(ctx)
+			if a == nil || err != nil || res == nil {
+				return res, err
+			}
+
+			_, _ = a.load(res) //nolint: errcheck
+
+			return &result{
+				parent:           res,
+				lastInsertIDFunc: res.LastInsertId,
+				rowsAffectedFunc: func() (int64, error) {
+					return a.load(res)
+				},
+			}, nil
+		}
+	}
+}
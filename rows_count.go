@@ -0,0 +1,65 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// rowsCount counts the rows and the approximate size of the values that are returned by a query.
+type rowsCount struct {
+	rows  int64
+	bytes int64
+}
+
+func (c *rowsCount) count(dest []driver.Value) {
+	c.rows++
+
+	for _, v := range dest {
+		switch v := v.(type) {
+		case []byte:
+			c.bytes += int64(len(v))
+
+		case string:
+			c.bytes += int64(len(v))
+		}
+	}
+}
+
+func (c *rowsCount) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		dbResponseReturnedRows.Int64(c.rows),
+		dbResponseReturnedBytes.Int64(c.bytes),
+	}
+}
+
+// queryCountRows counts the rows returned by query, adds the totals to the query span and records them when the rows
+// are closed.
+func queryCountRows(r rowsRecorder, method string) queryContextFuncMiddleware {
+	return func(next queryContextFunc) queryContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+			ctx, h, attached := contextWithRowsHooks(ctx)
+
+			result, err := next(ctx, query, args)
+			if err != nil {
+				return nil, err
+			}
+
+			c := &rowsCount{}
+			ctx = detachContext(ctx)
+
+			h.attributes = append(h.attributes, c.attributes)
+			h.onNext = append(h.onNext, func(dest []driver.Value, err error) {
+				if err == nil {
+					c.count(dest)
+				}
+			})
+			h.onClose = append(h.onClose, func() {
+				r.RecordRows(ctx, method, c.rows, c.bytes)
+			})
+
+			return h.wrap(result, attached), nil
+		}
+	}
+}
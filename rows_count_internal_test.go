@@ -0,0 +1,26 @@
+package otelsql
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRowsCount_Count(t *testing.T) {
+	t.Parallel()
+
+	c := &rowsCount{}
+
+	c.count([]driver.Value{"US", []byte("John"), int64(42), nil})
+	c.count([]driver.Value{"CA", []byte("Alice"), 4.2, time.Now()})
+
+	expected := []attribute.KeyValue{
+		dbResponseReturnedRows.Int64(2),
+		dbResponseReturnedBytes.Int64(13),
+	}
+
+	assert.Equal(t, expected, c.attributes())
+}
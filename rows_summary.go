@@ -0,0 +1,127 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	xattr "go.nhat.io/otelsql/attribute"
+)
+
+// rowsNextSummary summarizes the iteration of the rows returned by a query.
+type rowsNextSummary struct {
+	// start is when the query started, returned is when it returned the rows.
+	start    time.Time
+	returned time.Time
+	// lastRow is when the latest row was read, end is when the iteration ended with an error, io.EOF or the rows close.
+	lastRow time.Time
+	end     time.Time
+
+	count           int64
+	firstRowLatency time.Duration
+	maxGap          time.Duration
+}
+
+func (s *rowsNextSummary) next(now time.Time, err error) {
+	if !s.end.IsZero() {
+		return
+	}
+
+	if err != nil {
+		s.end = now
+
+		return
+	}
+
+	if s.count == 0 {
+		s.firstRowLatency = now.Sub(s.start)
+	} else {
+		s.maxGap = max(s.maxGap, now.Sub(s.lastRow))
+	}
+
+	s.count++
+	s.lastRow = now
+}
+
+func (s *rowsNextSummary) close(now time.Time) {
+	if s.end.IsZero() {
+		s.end = now
+	}
+}
+
+// duration is the time since the query started until the iteration ended.
+func (s *rowsNextSummary) duration() time.Duration {
+	return s.end.Sub(s.start)
+}
+
+func (s *rowsNextSummary) timingAttributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2)
+
+	if s.count > 0 {
+		attrs = append(attrs, xattr.KeyValueDuration(dbSQLRowsNextFirstRowLatency, s.firstRowLatency))
+	}
+
+	attrs = append(attrs, xattr.KeyValueDuration(dbSQLRowsDuration, s.duration()))
+
+	return attrs
+}
+
+func (s *rowsNextSummary) attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+
+	attrs = append(attrs,
+		dbSQLRowsNextSuccessCount.Int64(s.count),
+		xattr.KeyValueDuration(dbSQLRowsNextIterationTime, s.end.Sub(s.returned)),
+	)
+
+	if s.count > 1 {
+		attrs = append(attrs, xattr.KeyValueDuration(dbSQLRowsNextMaxGap, s.maxGap))
+	}
+
+	return attrs
+}
+
+// queryRowsNextSummary summarizes the iteration of the rows returned by query. When the rows are closed, it records
+// the time to the first row and the duration of the response, and adds them to the query span, with the summary if
+// traceSummary is true.
+func queryRowsNextSummary(r rowsRecorder, method string, traceSummary bool) queryContextFuncMiddleware {
+	return func(next queryContextFunc) queryContextFunc {
+		return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+			s := &rowsNextSummary{start: time.Now()}
+
+			ctx, h, attached := contextWithRowsHooks(ctx)
+
+			result, err := next(ctx, query, args)
+			if err != nil {
+				return nil, err
+			}
+
+			s.returned = time.Now()
+			ctx = detachContext(ctx)
+
+			h.attributes = append(h.attributes, s.timingAttributes)
+
+			if traceSummary {
+				h.attributes = append(h.attributes, s.attributes)
+			}
+
+			h.onNext = append(h.onNext, func(_ []driver.Value, err error) {
+				s.next(time.Now(), err)
+			})
+
+			h.onClose = append(h.onClose, func() {
+				s.close(time.Now())
+
+				if s.count > 0 {
+					r.RecordTimeToFirstRow(ctx, method, s.firstRowLatency)
+				}
+
+				r.RecordResponseDuration(ctx, method, s.duration())
+			})
+
+			return h.wrap(result, attached), nil
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package otelsql
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRowsNextSummary(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	testCases := []struct {
+		scenario       string
+		iterate        func(s *rowsNextSummary)
+		expectedTiming []attribute.KeyValue
+		expected       []attribute.KeyValue
+	}{
+		{
+			scenario: "no rows",
+			iterate: func(s *rowsNextSummary) {
+				s.next(at(15), io.EOF)
+				s.close(at(20))
+			},
+			expectedTiming: []attribute.KeyValue{
+				dbSQLRowsDuration.String("15ms"),
+			},
+			expected: []attribute.KeyValue{
+				dbSQLRowsNextSuccessCount.Int64(0),
+				dbSQLRowsNextIterationTime.String("5ms"),
+			},
+		},
+		{
+			scenario: "one row",
+			iterate: func(s *rowsNextSummary) {
+				s.next(at(20), nil)
+				s.close(at(30))
+			},
+			expectedTiming: []attribute.KeyValue{
+				dbSQLRowsNextFirstRowLatency.String("20ms"),
+				dbSQLRowsDuration.String("30ms"),
+			},
+			expected: []attribute.KeyValue{
+				dbSQLRowsNextSuccessCount.Int64(1),
+				dbSQLRowsNextIterationTime.String("20ms"),
+			},
+		},
+		{
+			scenario: "many rows until eof",
+			iterate: func(s *rowsNextSummary) {
+				s.next(at(20), nil)
+				s.next(at(25), nil)
+				s.next(at(45), nil)
+				s.next(at(50), io.EOF)
+				s.next(at(60), io.EOF)
+				s.close(at(70))
+			},
+			expectedTiming: []attribute.KeyValue{
+				dbSQLRowsNextFirstRowLatency.String("20ms"),
+				dbSQLRowsDuration.String("50ms"),
+			},
+			expected: []attribute.KeyValue{
+				dbSQLRowsNextSuccessCount.Int64(3),
+				dbSQLRowsNextIterationTime.String("40ms"),
+				dbSQLRowsNextMaxGap.String("20ms"),
+			},
+		},
+		{
+			scenario: "error",
+			iterate: func(s *rowsNextSummary) {
+				s.next(at(20), nil)
+				s.next(at(30), errors.New("next error"))
+				s.close(at(70))
+			},
+			expectedTiming: []attribute.KeyValue{
+				dbSQLRowsNextFirstRowLatency.String("20ms"),
+				dbSQLRowsDuration.String("30ms"),
+			},
+			expected: []attribute.KeyValue{
+				dbSQLRowsNextSuccessCount.Int64(1),
+				dbSQLRowsNextIterationTime.String("20ms"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := &rowsNextSummary{start: start, returned: at(10)}
+
+			tc.iterate(s)
+
+			assert.Equal(t, tc.expectedTiming, s.timingAttributes())
+			assert.Equal(t, tc.expected, s.attributes())
+		})
+	}
+}
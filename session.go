@@ -0,0 +1,78 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+)
+
+const (
+	metricMethodResetSession = "go.sql.conn.reset_session"
+	traceMethodResetSession  = "reset_session"
+)
+
+// resetSessionFuncMiddleware is a type for resetSessionFunc middleware.
+type resetSessionFuncMiddleware = middleware[resetSessionFunc]
+
+// resetSessionFunc is a callback for resetSessionFunc.
+type resetSessionFunc func(ctx context.Context) error
+
+// ResetSession satisfies driver.SessionResetter.
+func (f resetSessionFunc) ResetSession(ctx context.Context) error {
+	return f(ctx)
+}
+
+// resetSessionStats records reset session stats.
+func resetSessionStats(r methodRecorder) resetSessionFuncMiddleware {
+	return func(next resetSessionFunc) resetSessionFunc {
+		return func(ctx context.Context) (err error) {
+			end := r.Record(ctx, metricMethodResetSession)
+
+			defer func() {
+				end(err)
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// resetSessionDiscard counts the connections discarded by the pool because ResetSession returns driver.ErrBadConn.
+func resetSessionDiscard(r connRecorder) resetSessionFuncMiddleware {
+	return func(next resetSessionFunc) resetSessionFunc {
+		return func(ctx context.Context) error {
+			err := next(ctx)
+			if errors.Is(err, driver.ErrBadConn) {
+				r.RecordDiscard(ctx, connDiscardReasonResetSession)
+			}
+
+			return err
+		}
+	}
+}
+
+// resetSessionTrace traces reset session.
+func resetSessionTrace(t methodTracer) resetSessionFuncMiddleware {
+	return func(next resetSessionFunc) resetSessionFunc {
+		return func(ctx context.Context) (err error) {
+			ctx, end := t.Trace(ctx, traceMethodResetSession)
+
+			defer func() {
+				end(err)
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+func makeResetSessionFuncMiddlewares(r methodRecorder, cr connRecorder, t methodTracer) []resetSessionFuncMiddleware {
+	middlewares := make([]resetSessionFuncMiddleware, 0, 3)
+	middlewares = append(middlewares, resetSessionStats(r), resetSessionDiscard(cr))
+
+	if t != nil {
+		middlewares = append(middlewares, resetSessionTrace(t))
+	}
+
+	return middlewares
+}
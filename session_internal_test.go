@@ -0,0 +1,110 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+
+	"go.nhat.io/otelsql/internal/test/oteltest"
+)
+
+func TestResetSessionDiscard(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		error            error
+		expectedDiscards []string
+	}{
+		{
+			scenario: "no error",
+		},
+		{
+			scenario: "error",
+			error:    errors.New("error"),
+		},
+		{
+			scenario:         "bad connection",
+			error:            driver.ErrBadConn,
+			expectedDiscards: []string{connDiscardReasonResetSession},
+		},
+		{
+			scenario:         "wrapped bad connection",
+			error:            fmt.Errorf("reset: %w", driver.ErrBadConn),
+			expectedDiscards: []string{connDiscardReasonResetSession},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			r := &connRecorderFunc{}
+
+			reset := chainMiddlewares([]resetSessionFuncMiddleware{
+				resetSessionDiscard(r),
+			}, func(context.Context) error {
+				return tc.error
+			})
+
+			err := reset(context.Background())
+
+			assert.ErrorIs(t, err, tc.error)
+			assert.Equal(t, tc.expectedDiscards, r.discards)
+		})
+	}
+}
+
+func TestResetSessionStats(t *testing.T) {
+	t.Parallel()
+
+	expected := `[
+		{
+			"Name": "db.client.connection.discards{service.name=otelsql,instrumentation.name=session_test,db.client.connection.discard_reason=reset_session,db.instance=test,db.system=other_sql}",
+			"Sum": 1
+		},
+		{
+			"Name": "db.sql.client.calls{service.name=otelsql,instrumentation.name=session_test,db.instance=test,db.operation=go.sql.conn.reset_session,db.sql.error=driver: bad connection,db.sql.status=ERROR,db.system=other_sql}",
+			"Sum": 1
+		},
+		{
+			"Name": "db.sql.client.latency{service.name=otelsql,instrumentation.name=session_test,db.instance=test,db.operation=go.sql.conn.reset_session,db.sql.error=driver: bad connection,db.sql.status=ERROR,db.system=other_sql}",
+			"Sum": "<ignore-diff>",
+			"Count": 1
+		}
+	]`
+
+	oteltest.New(oteltest.MetricsEqualJSON(expected)).
+		Run(t, func(s oteltest.SuiteContext) {
+			meter := s.MeterProvider().Meter("session_test")
+
+			histogram, err := meter.Float64Histogram(dbSQLClientLatencyMs)
+			require.NoError(t, err)
+
+			count, err := meter.Int64Counter(dbSQLClientCalls)
+			require.NoError(t, err)
+
+			discards, err := meter.Int64Counter(dbClientConnectionDiscards)
+			require.NoError(t, err)
+
+			attrs := []attribute.KeyValue{semconv.DBSystemOtherSQL, dbInstance.String("test")}
+
+			reset := chainMiddlewares(makeResetSessionFuncMiddlewares(
+				newMethodRecorder(histogram.Record, count.Add, nil, attrs...),
+				newConnRecorder(nil, nil, nil, nil, discards.Add, attrs...),
+				nil,
+			), func(context.Context) error {
+				return driver.ErrBadConn
+			})
+
+			_ = reset(context.Background()) // nolint: errcheck
+		})
+}
@@ -0,0 +1,259 @@
+package otelsql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The SQLSTATE codes of the errors that are usually expected by the applications.
+const (
+	// SQLStateUniqueViolation is the SQLSTATE of a violation of a unique constraint.
+	SQLStateUniqueViolation = "23505"
+	// SQLStateSerializationFailure is the SQLSTATE of a transaction that cannot be serialized and should be retried.
+	SQLStateSerializationFailure = "40001"
+	// SQLStateDeadlockDetected is the SQLSTATE of a transaction that is aborted because of a deadlock in PostgreSQL.
+	SQLStateDeadlockDetected = "40P01"
+)
+
+// The vendor codes of the errors that are usually expected by the applications.
+const (
+	mysqlDuplicateEntry     = "1062"
+	mssqlUniqueIndexViolate = "2601"
+	mssqlUniqueKeyViolate   = "2627"
+	mssqlDeadlockVictim     = "1205"
+)
+
+// sqlErrorFields are the fields of a well-known error type of a driver.
+type sqlErrorFields struct {
+	sqlState string
+	code     string
+}
+
+// knownSQLErrors are the well-known error types of the drivers, by package path and type name. The errors are read with
+// reflection, so that the drivers do not need to be imported.
+var knownSQLErrors = map[string]sqlErrorFields{
+	"github.com/lib/pq.Error":                      {sqlState: "Code"},
+	"github.com/jackc/pgconn.PgError":              {sqlState: "Code"},
+	"github.com/jackc/pgx/v5/pgconn.PgError":       {sqlState: "Code"},
+	"github.com/go-sql-driver/mysql.MySQLError":    {sqlState: "SQLState", code: "Number"},
+	"github.com/microsoft/go-mssqldb.Error":        {code: "Number"},
+	"github.com/denisenkom/go-mssqldb.Error":       {code: "Number"},
+	"github.com/microsoft/go-mssqldb/mssql.Error":  {code: "Number"},
+	"github.com/denisenkom/go-mssqldb/mssql.Error": {code: "Number"},
+}
+
+// SQLError describes an error returned by a database.
+type SQLError struct {
+	// SQLState is the five-character SQLSTATE of the error, it is empty if the driver does not provide it.
+	SQLState string
+	// Code is the vendor code of the error, for example the error number of MySQL or SQL Server. It is empty if the
+	// driver does not provide it.
+	Code string
+	// Type is the type of the error returned by the driver, for example *pq.Error.
+	Type string
+}
+
+// Class returns the class of the SQLSTATE, its first two characters.
+func (e SQLError) Class() string {
+	if len(e.SQLState) < 2 {
+		return ""
+	}
+
+	return e.SQLState[:2]
+}
+
+// StatusCode returns the vendor code of the error if any, its SQLSTATE otherwise.
+func (e SQLError) StatusCode() string {
+	if e.Code != "" {
+		return e.Code
+	}
+
+	return e.SQLState
+}
+
+// IsUniqueViolation checks whether the error is a violation of a unique constraint.
+func (e SQLError) IsUniqueViolation() bool {
+	if e.SQLState != "" {
+		return e.SQLState == SQLStateUniqueViolation || e.Code == mysqlDuplicateEntry
+	}
+
+	// SQL Server does not provide the SQLSTATE.
+	return e.Code == mssqlUniqueIndexViolate || e.Code == mssqlUniqueKeyViolate
+}
+
+// IsSerializationFailure checks whether the error is a serialization failure or a deadlock, after which the transaction
+// should be retried.
+func (e SQLError) IsSerializationFailure() bool {
+	if e.SQLState != "" {
+		return e.SQLState == SQLStateSerializationFailure || e.SQLState == SQLStateDeadlockDetected
+	}
+
+	// SQL Server does not provide the SQLSTATE.
+	return e.Code == mssqlDeadlockVictim
+}
+
+func (e SQLError) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		dbResponseStatusCode.String(e.StatusCode()),
+		errorType.String(e.Type),
+	}
+}
+
+// InspectError extracts the SQLSTATE and the vendor code of the first error in the tree of err that has any. The
+// errors are recognized by the methods SQLState() string and SQLErrorNumber() int32, or by their types for lib/pq, pgx,
+// go-sql-driver/mysql and go-mssqldb.
+func InspectError(err error) (SQLError, bool) {
+	var result SQLError
+
+	found := walkErrors(err, func(err error) bool {
+		var ok bool
+
+		result, ok = inspectSQLError(err)
+
+		return ok
+	})
+
+	if !found {
+		return SQLError{}, false
+	}
+
+	return result, true
+}
+
+// walkErrors calls f with the errors of the tree of err, in depth-first order, until f returns true.
+func walkErrors(err error, f func(err error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if f(err) {
+		return true
+	}
+
+	switch e := err.(type) { //nolint: errorlint
+	case interface{ Unwrap() error }:
+		return walkErrors(e.Unwrap(), f)
+
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if walkErrors(err, f) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func inspectSQLError(err error) (SQLError, bool) {
+	result := SQLError{Type: fmt.Sprintf("%T", err)}
+
+	if e, ok := err.(interface{ SQLState() string }); ok { //nolint: errorlint
+		result.SQLState = e.SQLState()
+	}
+
+	if e, ok := err.(interface{ SQLErrorNumber() int32 }); ok { //nolint: errorlint
+		result.Code = strconv.FormatInt(int64(e.SQLErrorNumber()), 10)
+	}
+
+	if result.SQLState == "" || result.Code == "" {
+		readKnownSQLError(&result, reflect.ValueOf(err))
+	}
+
+	return result, result.SQLState != "" || result.Code != ""
+}
+
+// readKnownSQLError reads the missing SQLSTATE and vendor code from the fields of a well-known error type.
+func readKnownSQLError(result *SQLError, v reflect.Value) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	fields, ok := knownSQLErrors[v.Type().PkgPath()+"."+v.Type().Name()]
+	if !ok {
+		return
+	}
+
+	readSQLErrorFields(result, v, fields)
+}
+
+func readSQLErrorFields(result *SQLError, v reflect.Value, fields sqlErrorFields) {
+	if result.SQLState == "" && fields.sqlState != "" {
+		result.SQLState = fieldString(v.FieldByName(fields.sqlState))
+	}
+
+	if result.Code == "" && fields.code != "" {
+		result.Code = fieldString(v.FieldByName(fields.code))
+	}
+}
+
+// fieldString formats a string, an integer or a byte array field.
+func fieldString(v reflect.Value) string {
+	switch v.Kind() { //nolint: exhaustive
+	case reflect.String:
+		return v.String()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return ""
+		}
+
+		b := make([]byte, 0, v.Len())
+
+		for i := range v.Len() {
+			if c := byte(v.Index(i).Uint()); c != 0 {
+				b = append(b, c)
+			}
+		}
+
+		return string(b)
+	}
+
+	return ""
+}
+
+// errorAttributes returns the type of the error, and the status code of the database if the error has one.
+func errorAttributes(err error) []attribute.KeyValue {
+	if e, ok := InspectError(err); ok {
+		return e.attributes()
+	}
+
+	return []attribute.KeyValue{errorType.String(fmt.Sprintf("%T", err))}
+}
+
+// ignoreSQLErrors checks whether the error has an SQLSTATE or a vendor code that is matched by any of the matchers.
+func ignoreSQLErrors(err error, matchers []func(SQLError) bool) bool {
+	if err == nil || len(matchers) == 0 {
+		return false
+	}
+
+	e, ok := InspectError(err)
+	if !ok {
+		return false
+	}
+
+	for _, match := range matchers {
+		if match(e) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+package otelsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sql state " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+type sqlErrorNumberError int32
+
+func (e sqlErrorNumberError) Error() string {
+	return fmt.Sprintf("sql error %d", int32(e))
+}
+
+func (e sqlErrorNumberError) SQLErrorNumber() int32 {
+	return int32(e)
+}
+
+// mysqlError has the same fields as the errors of go-sql-driver/mysql.
+type mysqlError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlError) Error() string {
+	return e.Message
+}
+
+func TestInspectError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		error    error
+		expected SQLError
+		found    bool
+	}{
+		{
+			scenario: "nil",
+		},
+		{
+			scenario: "not an sql error",
+			error:    errors.New("error"),
+		},
+		{
+			scenario: "sql state",
+			error:    sqlStateError("23505"),
+			expected: SQLError{SQLState: "23505", Type: "otelsql.sqlStateError"},
+			found:    true,
+		},
+		{
+			scenario: "sql error number",
+			error:    sqlErrorNumberError(2627),
+			expected: SQLError{Code: "2627", Type: "otelsql.sqlErrorNumberError"},
+			found:    true,
+		},
+		{
+			scenario: "wrapped",
+			error:    fmt.Errorf("insert: %w", sqlStateError("40001")),
+			expected: SQLError{SQLState: "40001", Type: "otelsql.sqlStateError"},
+			found:    true,
+		},
+		{
+			scenario: "joined",
+			error:    errors.Join(errors.New("error"), fmt.Errorf("commit: %w", sqlStateError("40P01"))),
+			expected: SQLError{SQLState: "40P01", Type: "otelsql.sqlStateError"},
+			found:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, found := InspectError(tc.error)
+
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestReadSQLErrorFields(t *testing.T) {
+	t.Parallel()
+
+	err := &mysqlError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}, Message: "Duplicate entry"}
+	actual := SQLError{Type: "*mysql.MySQLError"}
+
+	readSQLErrorFields(&actual, reflect.ValueOf(err).Elem(), knownSQLErrors["github.com/go-sql-driver/mysql.MySQLError"])
+
+	expected := SQLError{SQLState: "23000", Code: "1062", Type: "*mysql.MySQLError"}
+
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "23", actual.Class())
+	assert.Equal(t, "1062", actual.StatusCode())
+	assert.True(t, actual.IsUniqueViolation())
+	assert.False(t, actual.IsSerializationFailure())
+}
+
+func TestSQLError_Classification(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario               string
+		error                  SQLError
+		isUniqueViolation      bool
+		isSerializationFailure bool
+	}{
+		{scenario: "postgres unique violation", error: SQLError{SQLState: "23505"}, isUniqueViolation: true},
+		{scenario: "postgres foreign key violation", error: SQLError{SQLState: "23503"}},
+		{scenario: "postgres serialization failure", error: SQLError{SQLState: "40001"}, isSerializationFailure: true},
+		{scenario: "postgres deadlock", error: SQLError{SQLState: "40P01"}, isSerializationFailure: true},
+		{scenario: "mysql deadlock", error: SQLError{SQLState: "40001", Code: "1213"}, isSerializationFailure: true},
+		{scenario: "mysql lock wait timeout", error: SQLError{SQLState: "HY000", Code: "1205"}},
+		{scenario: "sql server unique key", error: SQLError{Code: "2627"}, isUniqueViolation: true},
+		{scenario: "sql server unique index", error: SQLError{Code: "2601"}, isUniqueViolation: true},
+		{scenario: "sql server deadlock", error: SQLError{Code: "1205"}, isSerializationFailure: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.isUniqueViolation, tc.error.IsUniqueViolation())
+			assert.Equal(t, tc.isSerializationFailure, tc.error.IsSerializationFailure())
+		})
+	}
+}
+
+func TestErrorAttributes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []attribute.KeyValue{
+		dbResponseStatusCode.String("23505"),
+		errorType.String("otelsql.sqlStateError"),
+	}, errorAttributes(fmt.Errorf("insert: %w", sqlStateError("23505"))))
+
+	assert.Equal(t, []attribute.KeyValue{
+		errorType.String("*errors.errorString"),
+	}, errorAttributes(errors.New("error")))
+}
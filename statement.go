@@ -3,6 +3,13 @@ package otelsql
 import (
 	"context"
 	"database/sql/driver"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	xattr "go.nhat.io/otelsql/attribute"
 )
 
 const (
@@ -17,7 +24,64 @@ type columnConverter interface {
 	ColumnConverter(idx int) driver.ValueConverter
 }
 
+// wrappedStmt is a driver.Stmt that gives access to the underlying driver.Stmt.
+type wrappedStmt interface {
+	driver.Stmt
+
+	Unwrap() driver.Stmt
+}
+
+// stmtSequence generates the ids of the prepared statements.
+var stmtSequence atomic.Int64
+
+// stmtState holds the origin and the usage of a prepared statement.
+type stmtState struct {
+	info       QueryInfo
+	preparedAt time.Time
+	uses       atomic.Int64
+}
+
+func newStmtState(info QueryInfo) *stmtState {
+	return &stmtState{
+		info:       info,
+		preparedAt: time.Now(),
+	}
+}
+
+// context attaches the statement state and the query info of the statement to the context.
+func (s *stmtState) context(ctx context.Context) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	return ContextWithQueryInfo(context.WithValue(ctx, stmtStateCtxKey{}, s), s.info)
+}
+
+// use increases the use count of the statement and attaches the statement state to the context.
+func (s *stmtState) use(ctx context.Context) context.Context {
+	s.uses.Add(1)
+
+	return s.context(ctx)
+}
+
+func (s *stmtState) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		xattr.KeyValueDuration(dbSQLStmtAge, time.Since(s.preparedAt)),
+		dbSQLStmtUseCount.Int64(s.uses.Load()),
+	}
+}
+
+// links links the spans of the executions of the statement to the prepare span.
+func (s *stmtState) links() []trace.Link {
+	if !s.info.PrepareSpan.IsValid() {
+		return nil
+	}
+
+	return []trace.Link{{SpanContext: s.info.PrepareSpan}}
+}
+
 type stmt struct {
+	parent    driver.Stmt
 	stmtQuery string
 
 	exec        execContextFunc
@@ -26,8 +90,10 @@ type stmt struct {
 	query        queryContextFunc
 	queryContext queryContextFunc
 
-	close    func() error
+	close    closeFunc
 	numInput func() int
+	conn     *connState
+	state    *stmtState
 }
 
 func (s stmt) Exec(args []driver.Value) (res driver.Result, err error) {
@@ -35,13 +101,18 @@ func (s stmt) Exec(args []driver.Value) (res driver.Result, err error) {
 }
 
 func (s stmt) Close() error {
-	return s.close()
+	return s.close(s.state.context(s.conn.context(context.Background())))
 }
 
 func (s stmt) NumInput() int {
 	return s.numInput()
 }
 
+// Unwrap returns the underlying driver.Stmt.
+func (s stmt) Unwrap() driver.Stmt {
+	return s.parent
+}
+
 func (s stmt) Query(args []driver.Value) (rows driver.Rows, err error) {
 	return s.query(context.Background(), s.stmtQuery, valuesToNamedValues(args))
 }
@@ -56,149 +127,50 @@ func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows
 
 type stmtConfig struct {
 	query string
+	conn  *connState
+	state *stmtState
 
 	execFuncMiddlewares         []execContextFuncMiddleware
 	execContextFuncMiddlewares  []execContextFuncMiddleware
 	queryFuncMiddlewares        []queryContextFuncMiddleware
 	queryContextFuncMiddlewares []queryContextFuncMiddleware
+	closeFuncMiddlewares        []closeFuncMiddleware
 }
 
-// nolint: cyclop,funlen,gocyclo
 func wrapStmt(parent driver.Stmt, cfg stmtConfig) driver.Stmt {
 	s := makeStmt(parent, cfg)
 
 	var (
-		_, hasExeCtx    = parent.(driver.StmtExecContext)
-		_, hasQryCtx    = parent.(driver.StmtQueryContext)
-		c, hasColConv   = parent.(columnConverter)
-		n, hasNamValChk = parent.(driver.NamedValueChecker)
+		e driver.StmtExecContext
+		q driver.StmtQueryContext
 	)
 
-	switch {
-	default:
-		// case !hasExeCtx && !hasQryCtx && !hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-		}{s}
-
-	case !hasExeCtx && hasQryCtx && !hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtQueryContext
-		}{s, s}
-
-	case hasExeCtx && !hasQryCtx && !hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-		}{s, s}
-
-	case hasExeCtx && hasQryCtx && !hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			driver.StmtQueryContext
-		}{s, s, s}
-
-	case !hasExeCtx && !hasQryCtx && hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			columnConverter
-		}{s, c}
-
-	case !hasExeCtx && hasQryCtx && hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtQueryContext
-			columnConverter
-		}{s, s, c}
-
-	case hasExeCtx && !hasQryCtx && hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			columnConverter
-		}{s, s, c}
-
-	case hasExeCtx && hasQryCtx && hasColConv && !hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			driver.StmtQueryContext
-			columnConverter
-		}{s, s, s, c}
-
-	case !hasExeCtx && !hasQryCtx && !hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.NamedValueChecker
-		}{s, n}
-
-	case !hasExeCtx && hasQryCtx && !hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtQueryContext
-			driver.NamedValueChecker
-		}{s, s, n}
-
-	case hasExeCtx && !hasQryCtx && !hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			driver.NamedValueChecker
-		}{s, s, n}
-
-	case hasExeCtx && hasQryCtx && !hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			driver.StmtQueryContext
-			driver.NamedValueChecker
-		}{s, s, s, n}
-
-	case !hasExeCtx && !hasQryCtx && hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			columnConverter
-			driver.NamedValueChecker
-		}{s, c, n}
-
-	case !hasExeCtx && hasQryCtx && hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtQueryContext
-			columnConverter
-			driver.NamedValueChecker
-		}{s, s, c, n}
-
-	case hasExeCtx && !hasQryCtx && hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			columnConverter
-			driver.NamedValueChecker
-		}{s, s, c, n}
-
-	case hasExeCtx && hasQryCtx && hasColConv && hasNamValChk:
-		return struct {
-			driver.Stmt
-			driver.StmtExecContext
-			driver.StmtQueryContext
-			columnConverter
-			driver.NamedValueChecker
-		}{s, s, s, c, n}
+	if _, ok := parent.(driver.StmtExecContext); ok {
+		e = s
+	}
+
+	if _, ok := parent.(driver.StmtQueryContext); ok {
+		q = s
 	}
+
+	c, _ := parent.(columnConverter)          //nolint: errcheck
+	n, _ := parent.(driver.NamedValueChecker) //nolint: errcheck
+
+	return combineStmt(s, e, q, c, n)
 }
 
 func makeStmt(parent driver.Stmt, cfg stmtConfig) stmt {
 	return stmt{
+		parent:       parent,
 		stmtQuery:    cfg.query,
-		exec:         makeStmtExecFunc(parent, cfg.execFuncMiddlewares),
-		execContext:  makeStmtExecContextFunc(parent, cfg.execContextFuncMiddlewares),
-		query:        makeStmtQueryFunc(parent, cfg.queryFuncMiddlewares),
-		queryContext: makeStmtQueryContextFunc(parent, cfg.queryContextFuncMiddlewares),
-		close:        parent.Close,
+		exec:         stmtExecContext(cfg.state, connExecContext(cfg.conn, makeStmtExecFunc(parent, cfg.execFuncMiddlewares))),
+		execContext:  stmtExecContext(cfg.state, connExecContext(cfg.conn, makeStmtExecContextFunc(parent, cfg.execContextFuncMiddlewares))),
+		query:        stmtQueryContext(cfg.state, connQueryContext(cfg.conn, makeStmtQueryFunc(parent, cfg.queryFuncMiddlewares))),
+		queryContext: stmtQueryContext(cfg.state, connQueryContext(cfg.conn, makeStmtQueryContextFunc(parent, cfg.queryContextFuncMiddlewares))),
+		close:        chainMiddlewares(cfg.closeFuncMiddlewares, ensureClose(parent.Close)),
 		numInput:     parent.NumInput,
+		conn:         cfg.conn,
+		state:        cfg.state,
 	}
 }
 
@@ -235,3 +207,25 @@ func makeStmtQueryContextFunc(parent driver.Stmt, queryContextFuncMiddlewares []
 		return queryer.QueryContext(ctx, args)
 	})
 }
+
+// stmtExecContext counts the use of the statement before executing.
+func stmtExecContext(s *stmtState, next execContextFunc) execContextFunc {
+	if s == nil {
+		return next
+	}
+
+	return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+		return next(s.use(ctx), query, args)
+	}
+}
+
+// stmtQueryContext counts the use of the statement before querying.
+func stmtQueryContext(s *stmtState, next queryContextFunc) queryContextFunc {
+	if s == nil {
+		return next
+	}
+
+	return func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+		return next(s.use(ctx), query, args)
+	}
+}
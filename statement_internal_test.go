@@ -154,7 +154,7 @@ func TestWrapStmt(t *testing.T) {
 			scenario: "!hasExeCtx && !hasQryCtx && !hasColConv && !hasNamValChk",
 			parent:   parent,
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 			}{},
 			assert: func(*testing.T, driver.Stmt) {},
 		},
@@ -168,7 +168,7 @@ func TestWrapStmt(t *testing.T) {
 				StmtQueryContext: queryContextFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtQueryContext
 			}{},
 			assert: assertQueryContextFunc,
@@ -183,7 +183,7 @@ func TestWrapStmt(t *testing.T) {
 				StmtExecContext: execContextFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 			}{},
 			assert: assertExecContextFunc,
@@ -200,7 +200,7 @@ func TestWrapStmt(t *testing.T) {
 				StmtQueryContext: queryContextFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				driver.StmtQueryContext
 			}{},
@@ -221,7 +221,7 @@ func TestWrapStmt(t *testing.T) {
 				columnConverter: columnConverterFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				columnConverter
 			}{},
 			assert: assertColumnConverterFunc,
@@ -238,7 +238,7 @@ func TestWrapStmt(t *testing.T) {
 				columnConverter:  columnConverterFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtQueryContext
 				columnConverter
 			}{},
@@ -261,7 +261,7 @@ func TestWrapStmt(t *testing.T) {
 				columnConverter: columnConverterFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				columnConverter
 			}{},
@@ -286,7 +286,7 @@ func TestWrapStmt(t *testing.T) {
 				columnConverter:  columnConverterFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				driver.StmtQueryContext
 				columnConverter
@@ -309,7 +309,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.NamedValueChecker
 			}{},
 			assert: assertNamedValueCheckerFunc,
@@ -326,7 +326,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtQueryContext
 				driver.NamedValueChecker
 			}{},
@@ -349,7 +349,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				driver.NamedValueChecker
 			}{},
@@ -374,7 +374,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				driver.StmtQueryContext
 				driver.NamedValueChecker
@@ -399,7 +399,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				columnConverter
 				driver.NamedValueChecker
 			}{},
@@ -424,7 +424,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtQueryContext
 				columnConverter
 				driver.NamedValueChecker
@@ -451,7 +451,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				columnConverter
 				driver.NamedValueChecker
@@ -480,7 +480,7 @@ func TestWrapStmt(t *testing.T) {
 				NamedValueChecker: namedValueCheckerFunc,
 			},
 			expectedType: struct {
-				driver.Stmt
+				wrappedStmt
 				driver.StmtExecContext
 				driver.StmtQueryContext
 				columnConverter
@@ -525,6 +525,58 @@ func TestWrapStmt(t *testing.T) {
 	}
 }
 
+func TestStmt_CloseRecordLifetime(t *testing.T) {
+	t.Parallel()
+
+	parent := struct {
+		stmtCloseFunc
+		stmtNumInputFunc
+		stmtExecFunc
+		stmtQueryFunc
+	}{
+		stmtCloseFunc: func() error {
+			return nil
+		},
+		stmtExecFunc: func([]driver.Value) (driver.Result, error) {
+			return driver.ResultNoRows, nil
+		},
+	}
+
+	r := &stmtRecorderFunc{}
+	s := newStmtState(QueryInfo{})
+
+	stmt := wrapStmt(parent, stmtConfig{
+		state:                s,
+		closeFuncMiddlewares: []closeFuncMiddleware{stmtCloseRecordLifetime(r)},
+	})
+
+	_, _ = stmt.Exec(nil) // nolint: errcheck,staticcheck
+	_, _ = stmt.Exec(nil) // nolint: errcheck,staticcheck
+
+	require.NoError(t, stmt.Close())
+
+	assert.Same(t, s, r.state)
+	assert.Equal(t, int64(2), r.state.uses.Load())
+}
+
+type stmtRecorderFunc struct {
+	prepares []string
+	opened   int
+	state    *stmtState
+}
+
+func (r *stmtRecorderFunc) RecordPrepare(_ context.Context, query string) {
+	r.prepares = append(r.prepares, query)
+}
+
+func (r *stmtRecorderFunc) RecordOpen(context.Context) {
+	r.opened++
+}
+
+func (r *stmtRecorderFunc) RecordClose(_ context.Context, s *stmtState) {
+	r.state = s
+}
+
 type stmtCloseFunc func() error
 
 func (f stmtCloseFunc) Close() error {
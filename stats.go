@@ -3,12 +3,14 @@ package otelsql
 import (
 	"context"
 	"database/sql"
+	"slices"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
 )
 
 // defaultMinimumReadDBStatsInterval is the default minimum interval between calls to db.Stats().
@@ -23,10 +25,109 @@ const (
 	dbSQLConnectionsIdleClosed     = "db.sql.connections.idle_closed"
 	dbSQLConnectionsIdleTimeClosed = "db.sql.connections.idle_time_closed"
 	dbSQLConnectionsLifetimeClosed = "db.sql.connections.lifetime_closed"
+
+	dbClientConnectionCount           = "db.client.connection.count"
+	dbClientConnectionMax             = "db.client.connection.max"
+	dbClientConnectionPendingRequests = "db.client.connection.pending_requests"
+
+	dbClientConnectionPoolSaturation = "db.client.connection.pool.saturation"
+)
+
+const (
+	unitConnection = "{connection}"
+	unitRequest    = "{request}"
+	unitSeconds    = "s"
 )
 
 // RecordStats records database statistics for provided sql.DB at the provided interval.
+//
+// The statistics are recorded until the program exits, use RecordStatsWithRegistration to stop recording them.
 func RecordStats(db *sql.DB, opts ...StatsOption) error {
+	_, err := RecordStatsWithRegistration(db, opts...)
+
+	return err
+}
+
+// RecordStatsWithRegistration records database statistics for provided sql.DB at the provided interval, until the
+// returned registration is unregistered, for example after db.Close().
+func RecordStatsWithRegistration(db *sql.DB, opts ...StatsOption) (metric.Registration, error) {
+	r, err := NewStatsRecorder(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Observe(db)
+
+	return statsRegistration{recorder: r}, nil
+}
+
+// statsRegistration stops recording the statistics of the databases of a StatsRecorder and forgets them in the
+// PoolAnalyzer.
+type statsRegistration struct {
+	embedded.Registration
+
+	recorder *StatsRecorder
+}
+
+func (r statsRegistration) Unregister() error {
+	return r.recorder.Unregister()
+}
+
+// StatsRecorder records the statistics of many databases with a single callback. Each database is labelled by its own
+// attributes, in addition to the default attributes.
+//
+// It is useful for the applications that open and close the databases dynamically, for example one per tenant.
+type StatsRecorder struct {
+	registration metric.Registration
+
+	minimumReadDBStatsInterval time.Duration
+	attributes                 []attribute.KeyValue
+	analyzer                   *PoolAnalyzer
+
+	// mu prevents a race between the callback and the changes of the pools.
+	mu    sync.Mutex
+	pools []*dbStatsPool
+}
+
+type dbStatsPool struct {
+	db            *sql.DB
+	attributes    metric.MeasurementOption
+	attributesSet attribute.Set
+
+	stats     dbStats
+	lastStats time.Time
+}
+
+// dbStats are the statistics of a pool, with the ones derived from the previous reading.
+type dbStats struct {
+	sql.DBStats
+
+	// pendingRequests is the average number of calls waiting for a connection since the previous reading, it is only
+	// known from the second reading.
+	pendingRequests    float64
+	hasPendingRequests bool
+}
+
+// read reads the statistics of the pool.
+//
+// database/sql does not expose the number of calls waiting for a connection, but the time they waited in total. Over an
+// interval, the number of waiting calls is on average the time they waited divided by the duration of the interval.
+func (p *dbStatsPool) read(now time.Time) {
+	stats := dbStats{DBStats: p.db.Stats()}
+
+	if !p.lastStats.IsZero() {
+		if elapsed := now.Sub(p.lastStats); elapsed > 0 {
+			stats.pendingRequests = float64(stats.WaitDuration-p.stats.WaitDuration) / float64(elapsed)
+			stats.hasPendingRequests = true
+		}
+	}
+
+	p.stats = stats
+	p.lastStats = now
+}
+
+// NewStatsRecorder creates a new StatsRecorder. The recorder does not observe any database until Observe is called.
+func NewStatsRecorder(opts ...StatsOption) (*StatsRecorder, error) {
 	o := statsOptions{
 		meterProvider:              otel.GetMeterProvider(),
 		minimumReadDBStatsInterval: defaultMinimumReadDBStatsInterval,
@@ -36,18 +137,117 @@ func RecordStats(db *sql.DB, opts ...StatsOption) error {
 		opt.applyStatsOptions(&o)
 	}
 
-	meter := o.meterProvider.Meter(instrumentationName)
+	r := &StatsRecorder{
+		minimumReadDBStatsInterval: o.minimumReadDBStatsInterval,
+		attributes:                 o.defaultAttributes,
+		analyzer:                   o.analyzer,
+	}
+
+	registration, err := recordStats(o.meterProvider.Meter(instrumentationName), r.observe, r.analyzer != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.registration = registration
+
+	return r, nil
+}
+
+// Observe starts recording the statistics of the database with the given attributes. Observing the same database
+// again replaces its attributes.
+func (r *StatsRecorder) Observe(db *sql.DB, attrs ...attribute.KeyValue) {
+	all := make([]attribute.KeyValue, 0, len(r.attributes)+len(attrs))
+	all = append(all, r.attributes...)
+	all = append(all, attrs...)
+
+	set := attribute.NewSet(all...)
+
+	pool := &dbStatsPool{
+		db:            db,
+		attributes:    metric.WithAttributeSet(set),
+		attributesSet: set,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.pools {
+		if p.db == db {
+			r.pools[i] = pool
+
+			return
+		}
+	}
 
-	return recordStats(meter, db, o.minimumReadDBStatsInterval, o.defaultAttributes...)
+	r.pools = append(r.pools, pool)
 }
 
+// Forget stops recording the statistics of the database.
+func (r *StatsRecorder) Forget(db *sql.DB) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pools = slices.DeleteFunc(r.pools, func(p *dbStatsPool) bool {
+		return p.db == db
+	})
+
+	if r.analyzer != nil {
+		r.analyzer.forget(db)
+	}
+}
+
+// Unregister stops recording the statistics of all the databases.
+func (r *StatsRecorder) Unregister() error {
+	r.mu.Lock()
+
+	if r.analyzer != nil {
+		for _, p := range r.pools {
+			r.analyzer.forget(p.db)
+		}
+	}
+
+	r.pools = nil
+	r.mu.Unlock()
+
+	return r.registration.Unregister()
+}
+
+func (r *StatsRecorder) observe(ctx context.Context, f func(stats dbStats, attrs metric.MeasurementOption)) {
+	var alerts []PoolAlert
+
+	// The alerts are handled after unlocking, so that the handler can use the recorder.
+	defer func() {
+		for _, alert := range alerts {
+			r.analyzer.handleAlert(ctx, alert)
+		}
+	}()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	for _, p := range r.pools {
+		if now.Sub(p.lastStats) >= r.minimumReadDBStatsInterval {
+			p.read(now)
+
+			if r.analyzer != nil {
+				alerts = append(alerts, r.analyzer.analyze(p.db, p.attributesSet, p.stats.DBStats, now)...)
+			}
+		}
+
+		f(p.stats, p.attributes)
+	}
+}
+
+// recordStats records the statistics of the pools, and their saturation when they are analyzed.
+//
 // nolint: funlen
 func recordStats(
 	meter metric.Meter,
-	db *sql.DB,
-	minimumReadDBStatsInterval time.Duration,
-	attrs ...attribute.KeyValue,
-) error {
+	observe func(ctx context.Context, f func(stats dbStats, attrs metric.MeasurementOption)),
+	recordSaturation bool,
+) (metric.Registration, error) {
 	var (
 		err error
 
@@ -60,8 +260,10 @@ func recordStats(
 		idleTimeClosed    metric.Int64ObservableCounter
 		lifetimeClosed    metric.Int64ObservableCounter
 
-		dbStats     sql.DBStats
-		lastDBStats time.Time
+		connectionCount metric.Int64ObservableUpDownCounter
+		connectionMax   metric.Int64ObservableUpDownCounter
+		pendingRequests metric.Float64ObservableUpDownCounter
+		poolSaturation  metric.Float64ObservableGauge
 
 		// lock prevents a race between batch observer and instrument registration.
 		lock sync.Mutex
@@ -69,7 +271,6 @@ func recordStats(
 
 	lock.Lock()
 	defer lock.Unlock()
-
 	openConnections, err = meter.Int64ObservableGauge(
 		dbSQLConnectionsOpen,
 		metric.WithUnit(unitDimensionless),
@@ -126,27 +327,28 @@ func recordStats(
 	)
 	handleErr(err)
 
-	_, err = meter.RegisterCallback(func(_ context.Context, obs metric.Observer) error {
-		lock.Lock()
-		defer lock.Unlock()
+	connectionCount, err = meter.Int64ObservableUpDownCounter(
+		dbClientConnectionCount,
+		metric.WithUnit(unitConnection),
+		metric.WithDescription("The number of connections that are currently in state described by the state attribute"),
+	)
+	handleErr(err)
 
-		now := time.Now()
-		if now.Sub(lastDBStats) >= minimumReadDBStatsInterval {
-			dbStats = db.Stats()
-			lastDBStats = now
-		}
+	connectionMax, err = meter.Int64ObservableUpDownCounter(
+		dbClientConnectionMax,
+		metric.WithUnit(unitConnection),
+		metric.WithDescription("The maximum number of open connections allowed"),
+	)
+	handleErr(err)
 
-		obs.ObserveInt64(openConnections, int64(dbStats.OpenConnections), metric.WithAttributes(attrs...))
-		obs.ObserveInt64(idleConnections, int64(dbStats.Idle), metric.WithAttributes(attrs...))
-		obs.ObserveInt64(activeConnections, int64(dbStats.InUse), metric.WithAttributes(attrs...))
-		obs.ObserveInt64(waitCount, dbStats.WaitCount, metric.WithAttributes(attrs...))
-		obs.ObserveFloat64(waitDuration, float64(dbStats.WaitDuration.Nanoseconds())/1e6, metric.WithAttributes(attrs...))
-		obs.ObserveInt64(idleClosed, dbStats.MaxIdleClosed, metric.WithAttributes(attrs...))
-		obs.ObserveInt64(idleTimeClosed, dbStats.MaxIdleTimeClosed, metric.WithAttributes(attrs...))
-		obs.ObserveInt64(lifetimeClosed, dbStats.MaxLifetimeClosed, metric.WithAttributes(attrs...))
+	pendingRequests, err = meter.Float64ObservableUpDownCounter(
+		dbClientConnectionPendingRequests,
+		metric.WithUnit(unitRequest),
+		metric.WithDescription("The average number of pending requests for an open connection since the previous observation"),
+	)
+	handleErr(err)
 
-		return nil
-	},
+	instruments := []metric.Observable{
 		openConnections,
 		idleConnections,
 		activeConnections,
@@ -155,7 +357,54 @@ func recordStats(
 		idleClosed,
 		idleTimeClosed,
 		lifetimeClosed,
-	)
+		connectionCount,
+		connectionMax,
+		pendingRequests,
+	}
 
-	return err
+	// The saturation is only relevant to the analyzed pools, see WithPoolAnalyzer.
+	if recordSaturation {
+		poolSaturation, err = meter.Float64ObservableGauge(
+			dbClientConnectionPoolSaturation,
+			metric.WithUnit(unitDimensionless),
+			metric.WithDescription("The ratio of the connections in use to the maximum number of open connections"),
+		)
+		handleErr(err)
+
+		instruments = append(instruments, poolSaturation)
+	}
+
+	return meter.RegisterCallback(func(ctx context.Context, obs metric.Observer) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		observe(ctx, func(dbStats dbStats, attrs metric.MeasurementOption) {
+			obs.ObserveInt64(openConnections, int64(dbStats.OpenConnections), attrs)
+			obs.ObserveInt64(idleConnections, int64(dbStats.Idle), attrs)
+			obs.ObserveInt64(activeConnections, int64(dbStats.InUse), attrs)
+			obs.ObserveInt64(waitCount, dbStats.WaitCount, attrs)
+			obs.ObserveFloat64(waitDuration, float64(dbStats.WaitDuration.Nanoseconds())/1e6, attrs)
+			obs.ObserveInt64(idleClosed, dbStats.MaxIdleClosed, attrs)
+			obs.ObserveInt64(idleTimeClosed, dbStats.MaxIdleTimeClosed, attrs)
+			obs.ObserveInt64(lifetimeClosed, dbStats.MaxLifetimeClosed, attrs)
+
+			obs.ObserveInt64(connectionCount, int64(dbStats.Idle), attrs, metric.WithAttributes(dbClientConnectionStateIdle))
+			obs.ObserveInt64(connectionCount, int64(dbStats.InUse), attrs, metric.WithAttributes(dbClientConnectionStateUsed))
+
+			if dbStats.hasPendingRequests {
+				obs.ObserveFloat64(pendingRequests, dbStats.pendingRequests, attrs)
+			}
+
+			// Zero means unlimited, there is no maximum to report.
+			if dbStats.MaxOpenConnections > 0 {
+				obs.ObserveInt64(connectionMax, int64(dbStats.MaxOpenConnections), attrs)
+
+				if recordSaturation {
+					obs.ObserveFloat64(poolSaturation, float64(dbStats.InUse)/float64(dbStats.MaxOpenConnections), attrs)
+				}
+			}
+		})
+
+		return nil
+	}, instruments...)
 }
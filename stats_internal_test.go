@@ -0,0 +1,90 @@
+package otelsql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestStatsRecorder_PendingRequests(t *testing.T) {
+	t.Parallel()
+
+	r := &StatsRecorder{}
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close() // nolint: errcheck
+	})
+
+	db.SetMaxOpenConns(1)
+
+	var stats []dbStats
+
+	observe := func(s dbStats, _ metric.MeasurementOption) {
+		stats = append(stats, s)
+	}
+
+	r.Observe(db)
+	r.observe(context.Background(), observe)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		_ = conn.Close() // nolint: errcheck
+	}()
+
+	// Waits for the first connection to be released.
+	conn, err = db.Conn(context.Background())
+	require.NoError(t, err)
+
+	err = conn.Close()
+	require.NoError(t, err)
+
+	r.observe(context.Background(), observe)
+
+	require.Len(t, stats, 2)
+
+	// The pending requests are unknown until the second reading.
+	assert.False(t, stats[0].hasPendingRequests)
+
+	// A call waited for most of the interval.
+	assert.True(t, stats[1].hasPendingRequests)
+	assert.Greater(t, stats[1].pendingRequests, 0.5)
+	assert.LessOrEqual(t, stats[1].pendingRequests, 1.0)
+}
+
+func TestRecordStatsWithRegistration_Unregister_ForgetAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close() // nolint: errcheck
+	})
+
+	a := NewPoolAnalyzer()
+
+	registration, err := RecordStatsWithRegistration(db, WithPoolAnalyzer(a))
+	require.NoError(t, err)
+
+	a.analyze(db, attribute.NewSet(), db.Stats(), time.Now())
+
+	require.Contains(t, a.pools, db)
+
+	err = registration.Unregister()
+	require.NoError(t, err)
+
+	assert.NotContains(t, a.pools, db)
+}
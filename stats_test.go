@@ -1,10 +1,12 @@
 package otelsql_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 
 	"go.nhat.io/otelsql"
@@ -40,6 +42,143 @@ func TestRecordStats(t *testing.T) {
 		})
 }
 
+func TestRecordStatsWithRegistration_Unregister(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(`[]`),
+		oteltest.MockDatabase(func(m sqlmock.Sqlmock) {
+			m.ExpectPing()
+		}),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, err := newDB(sc.DatabaseDSN())
+			require.NoError(t, err)
+
+			registration, err := otelsql.RecordStatsWithRegistration(db,
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+			)
+			require.NoError(t, err)
+
+			err = registration.Unregister()
+			require.NoError(t, err)
+
+			err = db.Ping()
+			require.NoError(t, err)
+		})
+}
+
+func TestStatsRecorder(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("stats_pools.json")),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			r, err := otelsql.NewStatsRecorder(
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithInstanceName("default"),
+				otelsql.WithSystem(semconv.DBSystemPostgreSQL),
+			)
+			require.NoError(t, err)
+
+			for _, tenant := range []string{"a", "b", "c"} {
+				db, _, err := sqlmock.New()
+				require.NoError(t, err)
+
+				t.Cleanup(func() {
+					_ = db.Close() // nolint: errcheck
+				})
+
+				db.SetMaxOpenConns(10)
+
+				r.Observe(db, attribute.String("tenant", tenant))
+
+				if tenant == "c" {
+					r.Forget(db)
+				}
+			}
+		})
+}
+
+func TestStatsRecorder_WaitCount(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("stats_wait_count.json")),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, _, err := sqlmock.New()
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = db.Close() // nolint: errcheck
+			})
+
+			db.SetMaxOpenConns(1)
+
+			r, err := otelsql.NewStatsRecorder(
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithPoolName("primary"),
+			)
+			require.NoError(t, err)
+
+			r.Observe(db)
+
+			ctx := context.Background()
+
+			conn, err := db.Conn(ctx)
+			require.NoError(t, err)
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+
+				_ = conn.Close() // nolint: errcheck
+			}()
+
+			// Waits for the first connection to be released.
+			conn, err = db.Conn(ctx)
+			require.NoError(t, err)
+
+			err = conn.Close()
+			require.NoError(t, err)
+		})
+}
+
+func TestStatsRecorder_PoolAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	oteltest.New(
+		oteltest.MetricsEqualJSON(expectedMetricsFromFile("stats_pool_analyzer.json")),
+	).
+		Run(t, func(sc oteltest.SuiteContext) {
+			db, _, err := sqlmock.New()
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = db.Close() // nolint: errcheck
+			})
+
+			db.SetMaxOpenConns(2)
+
+			r, err := otelsql.NewStatsRecorder(
+				otelsql.WithMeterProvider(sc.MeterProvider()),
+				otelsql.WithPoolName("primary"),
+				otelsql.WithPoolAnalyzer(otelsql.NewPoolAnalyzer()),
+			)
+			require.NoError(t, err)
+
+			r.Observe(db)
+
+			conn, err := db.Conn(context.Background())
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = conn.Close() // nolint: errcheck
+			})
+		})
+}
+
 func expectedStatsMetric() string {
 	return expectedMetricsFromFile("stats.json")
 }
@@ -18,6 +18,15 @@ type spanNameFormatter func(ctx context.Context, op string) string
 
 type errorToSpanStatus func(err error) (codes.Code, string)
 
+type errorToSpanStatusContext func(ctx context.Context, err error) (codes.Code, string)
+
+// withContext converts the error converter to one that receives the context of the span.
+func (f errorToSpanStatus) withContext() errorToSpanStatusContext {
+	return func(_ context.Context, err error) (codes.Code, string) {
+		return f(err)
+	}
+}
+
 type queryTracer func(ctx context.Context, query string, args []driver.NamedValue) []attribute.KeyValue
 
 // methodTracer traces a sql method.
@@ -31,10 +40,16 @@ type methodTracer interface {
 type methodTracerImpl struct {
 	tracer trace.Tracer
 
-	formatSpanName spanNameFormatter
-	errorToStatus  func(err error) (codes.Code, string)
-	allowRoot      bool
-	attributes     []attribute.KeyValue
+	formatSpanName   spanNameFormatter
+	errorToStatus    errorToSpanStatusContext
+	ignoredSQLErrors []func(SQLError) bool
+	errorPolicies    errorPolicies
+	caller           *callerOptions
+	sqlErrors        bool
+	allowRoot        bool
+	connection       bool
+	statement        bool
+	attributes       []attribute.KeyValue
 }
 
 func (t *methodTracerImpl) ShouldTrace(ctx context.Context) (bool, bool) {
@@ -53,49 +68,121 @@ func (t *methodTracerImpl) Trace(ctx context.Context, method string, labels ...a
 	newCtx, end := t.MustTrace(ctx, method, labels...)
 
 	if !hasParentSpan {
-		ctx = newCtx
+		return newCtx, end
 	}
 
-	return ctx, end
+	// The new span is not attached to the context, but its operation is.
+	return contextWithOperationOf(ctx, newCtx), end
 }
 
 func (t *methodTracerImpl) MustTrace(ctx context.Context, method string, labels ...attribute.KeyValue) (context.Context, func(err error, attrs ...attribute.KeyValue)) {
-	ctx, span := t.tracer.Start(ctx, t.formatSpanName(ctx, method), //nolint: spancheck
-		trace.WithSpanKind(trace.SpanKindClient),
-	)
+	stmt := stmtStateFromContext(ctx)
+	if !t.statement {
+		stmt = nil
+	}
+
+	ctx = contextWithOperation(ctx, method)
+	info, _ := OperationInfoFromContext(ctx)
+
+	opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(info.StartTime)}
+
+	if stmt != nil {
+		opts = append(opts, trace.WithLinks(stmt.links()...))
+	}
+
+	ctx, span := t.tracer.Start(ctx, t.formatSpanName(ctx, method), opts...) //nolint: spancheck
 	if !span.IsRecording() {
 		return ctx, func(_ error, _ ...attribute.KeyValue) {
 			span.End()
 		}
 	}
 
-	attrs := make([]attribute.KeyValue, 0, len(t.attributes)+len(labels)+1)
+	attrs := make([]attribute.KeyValue, 0, len(t.attributes)+len(labels)+4)
 
 	attrs = append(attrs, t.attributes...)
 	attrs = append(attrs, labels...)
 	attrs = append(attrs, semconv.DBOperationKey.String(method))
 
+	if s := connStateFromContext(ctx); t.connection && s != nil {
+		attrs = append(attrs, s.attributes(ctx)...)
+
+		if waitTime, ok := s.takeWaitTime(); ok {
+			attrs = append(attrs, waitTime)
+		}
+	}
+
+	if stmt != nil {
+		attrs = append(attrs, stmt.attributes()...)
+	}
+
+	var callers []uintptr
+
+	if t.caller.captures(method) {
+		callers = t.caller.callers()
+		attrs = append(attrs, t.caller.attributes(callers)...)
+	}
+
 	return ctx, func(err error, labels ...attribute.KeyValue) { //nolint: spancheck
-		code, desc := t.errorToStatus(err)
+		status := t.errorPolicies.status(err)
+		code, desc := t.spanStatus(ctx, err, status)
 
 		attrs = append(attrs, labels...)
 
+		if status == ErrorStatusCanceled {
+			attrs = append(attrs, dbSQLStatusCANCELED)
+		}
+
+		if err != nil && t.sqlErrors {
+			attrs = append(attrs, errorAttributes(err)...)
+		}
+
 		span.SetAttributes(attrs...)
 		span.SetStatus(code, desc)
 
 		if code == codes.Error {
-			span.RecordError(err)
+			span.RecordError(err, t.errorEventOptions(callers)...)
 		}
 
 		span.End()
 	}
 }
 
+// errorEventOptions adds the stack trace of the caller to the exception event, if enabled.
+func (t *methodTracerImpl) errorEventOptions(callers []uintptr) []trace.EventOption {
+	if len(callers) == 0 || !t.caller.stackTrace {
+		return nil
+	}
+
+	return []trace.EventOption{
+		trace.WithAttributes(semconv.ExceptionStacktraceKey.String(t.caller.formatStackTrace(callers))),
+	}
+}
+
+// spanStatus converts the error of the method to the status of its span.
+func (t *methodTracerImpl) spanStatus(ctx context.Context, err error, status ErrorStatus) (codes.Code, string) {
+	if err != nil {
+		switch {
+		case status == ErrorStatusOK:
+			return codes.Ok, ""
+
+		// The canceled calls are neither failed nor successful.
+		case status == ErrorStatusCanceled:
+			return codes.Unset, ""
+
+		// The ignored errors are still recorded as errors by the metrics.
+		case ignoreSQLErrors(err, t.ignoredSQLErrors):
+			return codes.Ok, ""
+		}
+	}
+
+	return t.errorToStatus(ctx, err)
+}
+
 func newMethodTracer(tracer trace.Tracer, opts ...func(t *methodTracerImpl)) *methodTracerImpl {
 	t := &methodTracerImpl{
 		tracer:         tracer,
 		formatSpanName: formatSpanName,
-		errorToStatus:  spanStatusFromError,
+		errorToStatus:  errorToSpanStatus(spanStatusFromError).withContext(),
 	}
 
 	for _, o := range opts {
@@ -119,6 +206,44 @@ func traceWithAllowRoot(allow bool) func(t *methodTracerImpl) {
 	}
 }
 
+func traceWithConnection(enabled bool) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		t.connection = enabled
+	}
+}
+
+func traceWithStatement(enabled bool) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		t.statement = enabled
+	}
+}
+
+func traceWithSQLErrors(enabled bool) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		t.sqlErrors = enabled
+	}
+}
+
+func traceWithIgnoredSQLErrors(matchers []func(SQLError) bool) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		t.ignoredSQLErrors = matchers
+	}
+}
+
+func traceWithErrorPolicies(policies errorPolicies) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		t.errorPolicies = policies
+	}
+}
+
+func traceWithCaller(enabled bool, opts callerOptions) func(t *methodTracerImpl) {
+	return func(t *methodTracerImpl) {
+		if enabled {
+			t.caller = &opts
+		}
+	}
+}
+
 func traceWithDefaultAttributes(attrs ...attribute.KeyValue) func(t *methodTracerImpl) {
 	return func(t *methodTracerImpl) {
 		t.attributes = append(t.attributes, attrs...)
@@ -131,7 +256,7 @@ func traceWithSpanNameFormatter(f spanNameFormatter) func(t *methodTracerImpl) {
 	}
 }
 
-func traceWithErrorToSpanStatus(f errorToSpanStatus) func(t *methodTracerImpl) {
+func traceWithErrorToSpanStatus(f errorToSpanStatusContext) func(t *methodTracerImpl) {
 	return func(t *methodTracerImpl) {
 		t.errorToStatus = f
 	}
@@ -155,12 +280,25 @@ func spanStatusFromError(err error) (codes.Code, string) {
 	return codes.Error, err.Error()
 }
 
-func spanStatusFromErrorIgnoreErrSkip(err error) (codes.Code, string) {
-	if err == nil || errors.Is(err, driver.ErrSkip) {
-		return codes.Ok, ""
+// ignoreErrSkip considers driver.ErrSkip as OK, and converts the other errors with f.
+func ignoreErrSkip(f errorToSpanStatusContext) errorToSpanStatusContext {
+	return func(ctx context.Context, err error) (codes.Code, string) {
+		if errors.Is(err, driver.ErrSkip) {
+			return codes.Ok, ""
+		}
+
+		return f(ctx, err)
 	}
+}
 
-	return codes.Error, err.Error()
+// skippedAttributes marks the spans of the calls that returned driver.ErrSkip, even when their status hides the error,
+// see DisableErrSkip. database/sql executes these calls again with a prepared statement.
+func skippedAttributes(err error) []attribute.KeyValue {
+	if !errors.Is(err, driver.ErrSkip) {
+		return nil
+	}
+
+	return []attribute.KeyValue{dbSQLSkipped.Bool(true)}
 }
 
 func traceNoQuery(context.Context, string, []driver.NamedValue) []attribute.KeyValue {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,7 +62,7 @@ func TestSpanStatusFromError(t *testing.T) {
 	}
 }
 
-func TestSpanStatusFromErrorIgnoreErrSkip(t *testing.T) {
+func TestIgnoreErrSkip(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
@@ -95,7 +96,7 @@ func TestSpanStatusFromErrorIgnoreErrSkip(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			t.Parallel()
 
-			code, description := spanStatusFromErrorIgnoreErrSkip(tc.error)
+			code, description := ignoreErrSkip(errorToSpanStatus(spanStatusFromError).withContext())(context.Background(), tc.error)
 
 			assert.Equal(t, tc.expectedCode, code)
 			assert.Equal(t, tc.expectedDescription, description)
@@ -103,10 +104,20 @@ func TestSpanStatusFromErrorIgnoreErrSkip(t *testing.T) {
 	}
 }
 
+func TestSkippedAttributes(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, skippedAttributes(nil))
+	assert.Empty(t, skippedAttributes(errors.New("error")))
+	assert.Equal(t, []attribute.KeyValue{dbSQLSkipped.Bool(true)}, skippedAttributes(driver.ErrSkip))
+	assert.Equal(t, []attribute.KeyValue{dbSQLSkipped.Bool(true)}, skippedAttributes(fmt.Errorf("wrapped: %w", driver.ErrSkip)))
+}
+
 func TestMustTrace(t *testing.T) {
 	tests := map[string]struct {
 		method string
 		labels []attribute.KeyValue
+		opts   []func(t *methodTracerImpl)
 
 		endErr    error
 		endLabels []attribute.KeyValue
@@ -135,6 +146,31 @@ func TestMustTrace(t *testing.T) {
 				Description: "error",
 			},
 		},
+		"records a canceled span": {
+			method: "exec",
+			opts:   []func(t *methodTracerImpl){traceWithErrorPolicies(errorPolicies{IgnoreContextCanceled})},
+			endErr: context.Canceled,
+			expectedLabels: []attribute.KeyValue{
+				semconv.DBOperationKey.String("exec"),
+				dbSQLStatusCANCELED,
+			},
+			expectedStatus: tracesdk.Status{
+				Code:        codes.Unset,
+				Description: "",
+			},
+		},
+		"records a span with an error ignored by policy": {
+			method: "query",
+			opts:   []func(t *methodTracerImpl){traceWithErrorPolicies(errorPolicies{IgnoreErrSkip})},
+			endErr: driver.ErrSkip,
+			expectedLabels: []attribute.KeyValue{
+				semconv.DBOperationKey.String("query"),
+			},
+			expectedStatus: tracesdk.Status{
+				Code:        codes.Ok,
+				Description: "",
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -147,6 +183,7 @@ func TestMustTrace(t *testing.T) {
 					tracesdk.WithSampler(tracesdk.AlwaysSample()),
 					tracesdk.WithSpanProcessor(recorder),
 				).Tracer(t.Name()),
+				tc.opts...,
 			)
 
 			newCtx, end := mTracer.MustTrace(ctx, tc.method, tc.labels...)
@@ -3,8 +3,6 @@ package otelsql
 import (
 	"context"
 	"database/sql/driver"
-
-	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -21,6 +19,8 @@ type txFuncMiddleware = middleware[txFunc]
 type txFunc func() error
 
 type tx struct {
+	parent driver.Tx
+
 	commit   txFunc
 	rollback txFunc
 }
@@ -33,12 +33,32 @@ func (t tx) Rollback() error {
 	return t.rollback()
 }
 
+// Unwrap returns the underlying driver.Tx.
+func (t tx) Unwrap() driver.Tx {
+	return t.parent
+}
+
 func wrapTx(ctx context.Context, parent driver.Tx, r methodRecorder, t methodTracer) driver.Tx {
-	ctx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	ctx = detachContext(ctx)
+	s := connStateFromContext(ctx)
 
 	return &tx{
-		commit:   chainMiddlewares(makeTxFuncMiddlewares(ctx, r, t, metricMethodCommit, traceMethodCommit), parent.Commit),
-		rollback: chainMiddlewares(makeTxFuncMiddlewares(ctx, r, t, metricMethodRollback, traceMethodRollback), parent.Rollback),
+		parent:   parent,
+		commit:   chainMiddlewares(makeTxFuncMiddlewares(ctx, r, t, metricMethodCommit, traceMethodCommit), txEnd(s, parent.Commit)),
+		rollback: chainMiddlewares(makeTxFuncMiddlewares(ctx, r, t, metricMethodRollback, traceMethodRollback), txEnd(s, parent.Rollback)),
+	}
+}
+
+// txEnd ends the transaction of the connection after the commit or the rollback.
+func txEnd(s *connState, next txFunc) txFunc {
+	if s == nil {
+		return next
+	}
+
+	return func() error {
+		defer s.inTx.Store(false)
+
+		return next()
 	}
 }
 
@@ -75,8 +95,8 @@ func txTrace(ctx context.Context, t methodTracer, method string) txFuncMiddlewar
 }
 
 func makeTxFuncMiddlewares(ctx context.Context, r methodRecorder, t methodTracer, metricMethod string, traceMethod string) []txFuncMiddleware {
-	middlewares := make([]txFuncMiddleware, 0, 2)
-	middlewares = append(middlewares, txStats(ctx, r, metricMethod))
+	middlewares := make([]txFuncMiddleware, 0, 3)
+	middlewares = append(middlewares, txStats(ctx, r, metricMethod), txQueryBudget(ctx))
 
 	if t != nil {
 		middlewares = append(middlewares, txTrace(ctx, t, traceMethod))
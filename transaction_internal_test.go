@@ -119,7 +119,7 @@ func TestTxStats(t *testing.T) {
 					count, err := meter.Int64Counter(dbSQLClientCalls)
 					require.NoError(t, err)
 
-					r := newMethodRecorder(histogram.Record, count.Add,
+					r := newMethodRecorder(histogram.Record, count.Add, nil,
 						semconv.DBSystemOtherSQL,
 						dbInstance.String("test"),
 					)
@@ -133,3 +133,19 @@ func TestTxStats(t *testing.T) {
 		})
 	}
 }
+
+func TestTxEnd(t *testing.T) {
+	t.Parallel()
+
+	s := newConnState()
+	s.inTx.Store(true)
+
+	err := txEnd(s, func() error {
+		assert.True(t, s.inTx.Load())
+
+		return errors.New("commit error")
+	})()
+
+	require.EqualError(t, err, "commit error")
+	assert.False(t, s.inTx.Load())
+}
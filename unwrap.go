@@ -0,0 +1,69 @@
+package otelsql
+
+import (
+	"database/sql/driver"
+)
+
+// Unwrap finds the first value in the chain of v that is a T. The chain consists of v itself followed by the values
+// obtained by repeatedly calling its Unwrap method, so it works with any driver wrapper that follows the same
+// convention.
+//
+// It is useful to access the driver-specific features that are hidden by the instrumentation, for example:
+//
+//	err := conn.Raw(func(driverConn any) error {
+//		pgxConn, ok := otelsql.Unwrap[*stdlib.Conn](driverConn)
+//		if !ok {
+//			return errors.New("not a pgx connection")
+//		}
+//
+//		// Use pgxConn.
+//
+//		return nil
+//	})
+//
+// The supported values are driver.Driver, driver.Connector, driver.Conn, driver.Stmt, driver.Rows, driver.Tx and
+// driver.Result.
+func Unwrap[T any](v any) (T, bool) {
+	for v != nil {
+		if t, ok := v.(T); ok {
+			return t, true
+		}
+
+		v = unwrap(v)
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// nolint: cyclop
+func unwrap(v any) any {
+	switch u := v.(type) {
+	case driverWithContext:
+		return u.Driver
+
+	case interface{ Unwrap() driver.Conn }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Stmt }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Rows }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Tx }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Result }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Connector }:
+		return u.Unwrap()
+
+	case interface{ Unwrap() driver.Driver }:
+		return u.Unwrap()
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unwrapConn struct {
+	connPrepareFunc
+	connCloseFunc
+	connBeginFunc
+}
+
+type unwrapStmt struct {
+	stmtCloseFunc
+	stmtNumInputFunc
+	stmtExecFunc
+	stmtQueryFunc
+}
+
+type unwrapRows struct {
+	rowsColumnFunc
+	rowsCloseFunc
+	rowsNextFunc
+}
+
+type unwrapTx struct{}
+
+func (unwrapTx) Commit() error   { return nil }
+func (unwrapTx) Rollback() error { return nil }
+
+type unwrapResult struct{}
+
+func (unwrapResult) LastInsertId() (int64, error) { return 0, nil }
+func (unwrapResult) RowsAffected() (int64, error) { return 0, nil }
+
+type unwrapConnector struct{}
+
+func (unwrapConnector) Connect(context.Context) (driver.Conn, error) { return nil, nil } //nolint: nilnil
+func (unwrapConnector) Driver() driver.Driver                        { return nil }
+
+type unwrapDriver struct{}
+
+func (unwrapDriver) Open(string) (driver.Conn, error) { return nil, nil } //nolint: nilnil
+
+type unwrapDriverContext struct {
+	unwrapDriver
+}
+
+func (unwrapDriverContext) OpenConnector(string) (driver.Connector, error) { return nil, nil } //nolint: nilnil
+
+func TestUnwrap(t *testing.T) {
+	t.Parallel()
+
+	tracer := newMethodTracer(nil)
+
+	assertUnwrap[unwrapConn](t, wrapConn(unwrapConn{}, connConfig{}))
+	assertUnwrap[unwrapStmt](t, wrapStmt(unwrapStmt{}, stmtConfig{}))
+	assertUnwrap[unwrapRows](t, wrapRows(context.Background(), unwrapRows{}, tracer, true, true))
+	assertUnwrap[unwrapTx](t, wrapTx(context.Background(), unwrapTx{}, nil, nil))
+	assertUnwrap[unwrapResult](t, wrapResult(context.Background(), unwrapResult{}, tracer, true, true, false))
+	assertUnwrap[unwrapDriver](t, Wrap(unwrapDriver{}))
+	assertUnwrap[unwrapDriverContext](t, Wrap(unwrapDriverContext{}))
+	assertUnwrap[unwrapConnector](t, otConnector{parent: unwrapConnector{}})
+}
+
+func TestUnwrap_Nested(t *testing.T) {
+	t.Parallel()
+
+	c := wrapConn(wrapConn(unwrapConn{}, connConfig{}), connConfig{})
+
+	assertUnwrap[unwrapConn](t, c)
+}
+
+func TestUnwrap_NotFound(t *testing.T) {
+	t.Parallel()
+
+	actual, ok := Unwrap[unwrapStmt](wrapConn(unwrapConn{}, connConfig{}))
+
+	assert.False(t, ok)
+	assert.Equal(t, unwrapStmt{}, actual)
+
+	_, ok = Unwrap[unwrapConn](nil)
+
+	assert.False(t, ok)
+}
+
+func TestUnwrap_Self(t *testing.T) {
+	t.Parallel()
+
+	c := wrapConn(unwrapConn{}, connConfig{})
+
+	actual, ok := Unwrap[driver.Conn](c)
+
+	assert.True(t, ok)
+	assert.IsType(t, conn{}, actual)
+}
+
+func assertUnwrap[T any](t *testing.T, wrapped any) {
+	t.Helper()
+
+	actual, ok := Unwrap[T](wrapped)
+
+	require.True(t, ok, "could not unwrap %T", wrapped)
+	assert.IsType(t, *new(T), actual)
+}
@@ -0,0 +1,37 @@
+package otelsql
+
+import (
+	"context"
+)
+
+// isValidFuncMiddleware is a type for isValidFunc middleware.
+type isValidFuncMiddleware = middleware[isValidFunc]
+
+// isValidFunc is a callback for isValidFunc.
+type isValidFunc func(ctx context.Context) bool
+
+// validatorFunc is a callback for driver.Validator.
+type validatorFunc func() bool
+
+// IsValid satisfies driver.Validator.
+func (f validatorFunc) IsValid() bool {
+	return f()
+}
+
+// isValidDiscard counts the connections discarded by the pool because IsValid returns false.
+func isValidDiscard(r connRecorder) isValidFuncMiddleware {
+	return func(next isValidFunc) isValidFunc {
+		return func(ctx context.Context) bool {
+			valid := next(ctx)
+			if !valid {
+				r.RecordDiscard(ctx, connDiscardReasonInvalid)
+			}
+
+			return valid
+		}
+	}
+}
+
+func makeIsValidFuncMiddlewares(cr connRecorder) []isValidFuncMiddleware {
+	return []isValidFuncMiddleware{isValidDiscard(cr)}
+}
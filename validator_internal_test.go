@@ -0,0 +1,61 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidDiscard(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		valid            bool
+		expectedDiscards []string
+	}{
+		{
+			scenario: "valid",
+			valid:    true,
+		},
+		{
+			scenario:         "invalid",
+			valid:            false,
+			expectedDiscards: []string{connDiscardReasonInvalid},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			r := &connRecorderFunc{}
+
+			isValid := chainMiddlewares(makeIsValidFuncMiddlewares(r), func(context.Context) bool {
+				return tc.valid
+			})
+
+			assert.Equal(t, tc.valid, isValid(context.Background()))
+			assert.Equal(t, tc.expectedDiscards, r.discards)
+		})
+	}
+}
+
+func TestWrapConn_Validator(t *testing.T) {
+	t.Parallel()
+
+	r := &connRecorderFunc{}
+
+	parent := combineConn(conn{}, nil, nil, nil, nil, nil, validatorFunc(func() bool {
+		return false
+	}))
+
+	c := wrapConn(parent, connConfig{isValidFuncMiddlewares: makeIsValidFuncMiddlewares(r)})
+
+	assert.Implements(t, (*driver.Validator)(nil), c)
+	assert.False(t, c.(driver.Validator).IsValid()) //nolint: errcheck
+	assert.Equal(t, []string{connDiscardReasonInvalid}, r.discards)
+}
@@ -0,0 +1,1096 @@
+// Code generated by go run ./internal/cmd/wrapgen; DO NOT EDIT.
+
+package otelsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// combineConn returns a driver.Conn that implements exactly the optional interfaces that are not nil.
+//
+// nolint: cyclop,funlen,gocyclo,maintidx
+func combineConn(c conn, p driver.Pinger, e driver.ExecerContext, q driver.QueryerContext, n driver.NamedValueChecker, s driver.SessionResetter, v driver.Validator) driver.Conn {
+	var mask uint
+
+	if p != nil {
+		mask |= 1 << 0
+	}
+
+	if e != nil {
+		mask |= 1 << 1
+	}
+
+	if q != nil {
+		mask |= 1 << 2
+	}
+
+	if n != nil {
+		mask |= 1 << 3
+	}
+
+	if s != nil {
+		mask |= 1 << 4
+	}
+
+	if v != nil {
+		mask |= 1 << 5
+	}
+
+	switch mask {
+	case 0:
+		return c
+	case 1:
+		return struct {
+			conn
+			driver.Pinger
+		}{c, p}
+	case 2:
+		return struct {
+			conn
+			driver.ExecerContext
+		}{c, e}
+	case 3:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+		}{c, p, e}
+	case 4:
+		return struct {
+			conn
+			driver.QueryerContext
+		}{c, q}
+	case 5:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+		}{c, p, q}
+	case 6:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+		}{c, e, q}
+	case 7:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+		}{c, p, e, q}
+	case 8:
+		return struct {
+			conn
+			driver.NamedValueChecker
+		}{c, n}
+	case 9:
+		return struct {
+			conn
+			driver.Pinger
+			driver.NamedValueChecker
+		}{c, p, n}
+	case 10:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.NamedValueChecker
+		}{c, e, n}
+	case 11:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.NamedValueChecker
+		}{c, p, e, n}
+	case 12:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.NamedValueChecker
+		}{c, q, n}
+	case 13:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.NamedValueChecker
+		}{c, p, q, n}
+	case 14:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+		}{c, e, q, n}
+	case 15:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+		}{c, p, e, q, n}
+	case 16:
+		return struct {
+			conn
+			driver.SessionResetter
+		}{c, s}
+	case 17:
+		return struct {
+			conn
+			driver.Pinger
+			driver.SessionResetter
+		}{c, p, s}
+	case 18:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.SessionResetter
+		}{c, e, s}
+	case 19:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.SessionResetter
+		}{c, p, e, s}
+	case 20:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.SessionResetter
+		}{c, q, s}
+	case 21:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.SessionResetter
+		}{c, p, q, s}
+	case 22:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.SessionResetter
+		}{c, e, q, s}
+	case 23:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.SessionResetter
+		}{c, p, e, q, s}
+	case 24:
+		return struct {
+			conn
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, n, s}
+	case 25:
+		return struct {
+			conn
+			driver.Pinger
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, p, n, s}
+	case 26:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, e, n, s}
+	case 27:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, p, e, n, s}
+	case 28:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, q, n, s}
+	case 29:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, p, q, n, s}
+	case 30:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, e, q, n, s}
+	case 31:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+		}{c, p, e, q, n, s}
+	case 32:
+		return struct {
+			conn
+			driver.Validator
+		}{c, v}
+	case 33:
+		return struct {
+			conn
+			driver.Pinger
+			driver.Validator
+		}{c, p, v}
+	case 34:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.Validator
+		}{c, e, v}
+	case 35:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.Validator
+		}{c, p, e, v}
+	case 36:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.Validator
+		}{c, q, v}
+	case 37:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.Validator
+		}{c, p, q, v}
+	case 38:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.Validator
+		}{c, e, q, v}
+	case 39:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.Validator
+		}{c, p, e, q, v}
+	case 40:
+		return struct {
+			conn
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, n, v}
+	case 41:
+		return struct {
+			conn
+			driver.Pinger
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, p, n, v}
+	case 42:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, e, n, v}
+	case 43:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, p, e, n, v}
+	case 44:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, q, n, v}
+	case 45:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, p, q, n, v}
+	case 46:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, e, q, n, v}
+	case 47:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.Validator
+		}{c, p, e, q, n, v}
+	case 48:
+		return struct {
+			conn
+			driver.SessionResetter
+			driver.Validator
+		}{c, s, v}
+	case 49:
+		return struct {
+			conn
+			driver.Pinger
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, s, v}
+	case 50:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, e, s, v}
+	case 51:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, e, s, v}
+	case 52:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, q, s, v}
+	case 53:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, q, s, v}
+	case 54:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, e, q, s, v}
+	case 55:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, e, q, s, v}
+	case 56:
+		return struct {
+			conn
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, n, s, v}
+	case 57:
+		return struct {
+			conn
+			driver.Pinger
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, n, s, v}
+	case 58:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, e, n, s, v}
+	case 59:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, e, n, s, v}
+	case 60:
+		return struct {
+			conn
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, q, n, s, v}
+	case 61:
+		return struct {
+			conn
+			driver.Pinger
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, q, n, s, v}
+	case 62:
+		return struct {
+			conn
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, e, q, n, s, v}
+	case 63:
+		return struct {
+			conn
+			driver.Pinger
+			driver.ExecerContext
+			driver.QueryerContext
+			driver.NamedValueChecker
+			driver.SessionResetter
+			driver.Validator
+		}{c, p, e, q, n, s, v}
+	}
+
+	panic(fmt.Sprintf("otelsql: unexpected combineConn mask %b", mask))
+}
+
+// combineStmt returns a driver.Stmt that implements exactly the optional interfaces that are not nil.
+//
+// nolint: cyclop,funlen,gocyclo,maintidx
+func combineStmt(s wrappedStmt, e driver.StmtExecContext, q driver.StmtQueryContext, c columnConverter, n driver.NamedValueChecker) driver.Stmt {
+	var mask uint
+
+	if e != nil {
+		mask |= 1 << 0
+	}
+
+	if q != nil {
+		mask |= 1 << 1
+	}
+
+	if c != nil {
+		mask |= 1 << 2
+	}
+
+	if n != nil {
+		mask |= 1 << 3
+	}
+
+	switch mask {
+	case 0:
+		return struct {
+			wrappedStmt
+		}{s}
+	case 1:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+		}{s, e}
+	case 2:
+		return struct {
+			wrappedStmt
+			driver.StmtQueryContext
+		}{s, q}
+	case 3:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			driver.StmtQueryContext
+		}{s, e, q}
+	case 4:
+		return struct {
+			wrappedStmt
+			columnConverter
+		}{s, c}
+	case 5:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			columnConverter
+		}{s, e, c}
+	case 6:
+		return struct {
+			wrappedStmt
+			driver.StmtQueryContext
+			columnConverter
+		}{s, q, c}
+	case 7:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			driver.StmtQueryContext
+			columnConverter
+		}{s, e, q, c}
+	case 8:
+		return struct {
+			wrappedStmt
+			driver.NamedValueChecker
+		}{s, n}
+	case 9:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			driver.NamedValueChecker
+		}{s, e, n}
+	case 10:
+		return struct {
+			wrappedStmt
+			driver.StmtQueryContext
+			driver.NamedValueChecker
+		}{s, q, n}
+	case 11:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			driver.StmtQueryContext
+			driver.NamedValueChecker
+		}{s, e, q, n}
+	case 12:
+		return struct {
+			wrappedStmt
+			columnConverter
+			driver.NamedValueChecker
+		}{s, c, n}
+	case 13:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			columnConverter
+			driver.NamedValueChecker
+		}{s, e, c, n}
+	case 14:
+		return struct {
+			wrappedStmt
+			driver.StmtQueryContext
+			columnConverter
+			driver.NamedValueChecker
+		}{s, q, c, n}
+	case 15:
+		return struct {
+			wrappedStmt
+			driver.StmtExecContext
+			driver.StmtQueryContext
+			columnConverter
+			driver.NamedValueChecker
+		}{s, e, q, c, n}
+	}
+
+	panic(fmt.Sprintf("otelsql: unexpected combineStmt mask %b", mask))
+}
+
+// combineRows returns a driver.Rows that implements exactly the optional interfaces that are not nil.
+//
+// nolint: cyclop,funlen,gocyclo,maintidx
+func combineRows(r rows, nrs withRowsNextResultSet, dtn withRowsColumnTypeDatabaseTypeName, l withRowsColumnTypeLength, n withRowsColumnTypeNullable, ps withRowsColumnTypePrecisionScale, st withRowsColumnTypeScanType) driver.Rows {
+	var mask uint
+
+	if nrs != nil {
+		mask |= 1 << 0
+	}
+
+	if dtn != nil {
+		mask |= 1 << 1
+	}
+
+	if l != nil {
+		mask |= 1 << 2
+	}
+
+	if n != nil {
+		mask |= 1 << 3
+	}
+
+	if ps != nil {
+		mask |= 1 << 4
+	}
+
+	if st != nil {
+		mask |= 1 << 5
+	}
+
+	switch mask {
+	case 0:
+		return r
+	case 1:
+		return struct {
+			rows
+			withRowsNextResultSet
+		}{r, nrs}
+	case 2:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+		}{r, dtn}
+	case 3:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+		}{r, nrs, dtn}
+	case 4:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+		}{r, l}
+	case 5:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+		}{r, nrs, l}
+	case 6:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+		}{r, dtn, l}
+	case 7:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+		}{r, nrs, dtn, l}
+	case 8:
+		return struct {
+			rows
+			withRowsColumnTypeNullable
+		}{r, n}
+	case 9:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeNullable
+		}{r, nrs, n}
+	case 10:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+		}{r, dtn, n}
+	case 11:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+		}{r, nrs, dtn, n}
+	case 12:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+		}{r, l, n}
+	case 13:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+		}{r, nrs, l, n}
+	case 14:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+		}{r, dtn, l, n}
+	case 15:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+		}{r, nrs, dtn, l, n}
+	case 16:
+		return struct {
+			rows
+			withRowsColumnTypePrecisionScale
+		}{r, ps}
+	case 17:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, ps}
+	case 18:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypePrecisionScale
+		}{r, dtn, ps}
+	case 19:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, dtn, ps}
+	case 20:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+		}{r, l, ps}
+	case 21:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, l, ps}
+	case 22:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+		}{r, dtn, l, ps}
+	case 23:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, dtn, l, ps}
+	case 24:
+		return struct {
+			rows
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, n, ps}
+	case 25:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, n, ps}
+	case 26:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, dtn, n, ps}
+	case 27:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, dtn, n, ps}
+	case 28:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, l, n, ps}
+	case 29:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, l, n, ps}
+	case 30:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, dtn, l, n, ps}
+	case 31:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+		}{r, nrs, dtn, l, n, ps}
+	case 32:
+		return struct {
+			rows
+			withRowsColumnTypeScanType
+		}{r, st}
+	case 33:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeScanType
+		}{r, nrs, st}
+	case 34:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeScanType
+		}{r, dtn, st}
+	case 35:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, st}
+	case 36:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypeScanType
+		}{r, l, st}
+	case 37:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypeScanType
+		}{r, nrs, l, st}
+	case 38:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeScanType
+		}{r, dtn, l, st}
+	case 39:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, l, st}
+	case 40:
+		return struct {
+			rows
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, n, st}
+	case 41:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, nrs, n, st}
+	case 42:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, dtn, n, st}
+	case 43:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, n, st}
+	case 44:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, l, n, st}
+	case 45:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, nrs, l, n, st}
+	case 46:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, dtn, l, n, st}
+	case 47:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, l, n, st}
+	case 48:
+		return struct {
+			rows
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, ps, st}
+	case 49:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, ps, st}
+	case 50:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, dtn, ps, st}
+	case 51:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, ps, st}
+	case 52:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, l, ps, st}
+	case 53:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, l, ps, st}
+	case 54:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, dtn, l, ps, st}
+	case 55:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, l, ps, st}
+	case 56:
+		return struct {
+			rows
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, n, ps, st}
+	case 57:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, n, ps, st}
+	case 58:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, dtn, n, ps, st}
+	case 59:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, n, ps, st}
+	case 60:
+		return struct {
+			rows
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, l, n, ps, st}
+	case 61:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, l, n, ps, st}
+	case 62:
+		return struct {
+			rows
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, dtn, l, n, ps, st}
+	case 63:
+		return struct {
+			rows
+			withRowsNextResultSet
+			withRowsColumnTypeDatabaseTypeName
+			withRowsColumnTypeLength
+			withRowsColumnTypeNullable
+			withRowsColumnTypePrecisionScale
+			withRowsColumnTypeScanType
+		}{r, nrs, dtn, l, n, ps, st}
+	}
+
+	panic(fmt.Sprintf("otelsql: unexpected combineRows mask %b", mask))
+}